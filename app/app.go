@@ -5,16 +5,22 @@ import (
 	"reflect"
 
 	"github.com/DiwashRai/svnty/commit"
+	"github.com/DiwashRai/svnty/config"
 	"github.com/DiwashRai/svnty/info"
 	"github.com/DiwashRai/svnty/status"
 	"github.com/DiwashRai/svnty/styles"
 	"github.com/DiwashRai/svnty/svn"
 	"github.com/DiwashRai/svnty/tui"
+	"github.com/DiwashRai/svnty/watcher"
 
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
 
+const hintBarHeight = 1
+
 type AppMode int
 
 const (
@@ -29,29 +35,58 @@ type Model struct {
 	StatusModel status.Model
 	CommitModel commit.Model
 	Mode        AppMode
+	Watcher     *watcher.Watcher
+	Keys        tui.KeyMap
+	help        help.Model
+	showHelp    bool
 	width       int
 	height      int
 }
 
-func New(svc svn.Service, logger *slog.Logger) Model {
+type Options struct {
+	WorkingCopyPath string
+	Config          config.Config
+}
+
+func New(svc svn.Service, logger *slog.Logger, opts Options) Model {
+	cfg := opts.Config
+	styles.Apply(styles.LoadTheme(logger, cfg.Theme))
+	keys, keyWarnings := tui.LoadKeyMap(logger)
 	model := Model{
 		SvnService: svc,
 		Logger:     logger,
+		Keys:       keys,
+		help:       tui.NewHelp(),
 		InfoModel:  info.Model{SvnService: svc},
 		StatusModel: status.Model{
-			SvnService: svc,
-			Logger:     logger,
-			Cursor:     status.Cursor{ElemType: status.HeaderElem},
+			SvnService:    svc,
+			Logger:        logger,
+			Keys:          keys,
+			Cursor:        status.Cursor{ElemType: status.HeaderElem},
+			Errs:          keyWarnings,
+			PageSize:      cfg.PageSize,
+			ScrollPadding: cfg.ScrollPadding,
+			Collapsed:     cfg.Collapsed(),
 		},
 		CommitModel: commit.Model{
 			SvnService:    svc,
 			Logger:        logger,
-			CommitHistory: svn.NewCommitHistory(logger),
+			Keys:          keys,
+			Author:        cfg.Author,
+			PageSize:      cfg.PageSize,
+			CommitHistory: svn.NewCommitHistory(logger, cfg.HistorySize),
+			Draft:         svn.NewDraft(logger, opts.WorkingCopyPath),
+			Linter:        commit.LoadLinter(logger),
+			Templates:     commit.LoadTemplates(logger),
+			Hooks:         commit.LoadHooks(logger),
 		},
 		Mode: StatusMode,
 	}
 
 	model.CommitModel.Init()
+	// the commit options belong to the commit model, the status panel menu
+	// only edits them
+	model.StatusModel.Menus = []tui.Menu{model.CommitModel.CommitMenu()}
 	return model
 }
 
@@ -59,10 +94,16 @@ func (m *Model) Init() tea.Cmd {
 	m.Logger.Info("App.Init()")
 	m.StatusModel.Init()
 	m.SvnService.Init()
-	return tea.Batch(
+	cmds := []tea.Cmd{
 		status.FetchInfoCmd(m.SvnService),
 		status.FetchStatusCmd(m.SvnService),
-	)
+		commit.FetchBugtraqCmd(m.SvnService),
+		commit.DraftTick(),
+	}
+	if m.Watcher != nil {
+		cmds = append(cmds, m.Watcher.Wait())
+	}
+	return tea.Batch(cmds...)
 }
 
 func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -75,6 +116,11 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.width, m.height = msg.Width, msg.Height
+		m.help.Width = msg.Width - 1
+		// the hint bar takes the bottom line
+		msg.Height -= hintBarHeight
+		m.CommitModel.Update(msg)
+		m.InfoModel.Update(msg)
 		m.StatusModel.Update(msg)
 		return m, nil
 	case tui.StatusModeMsg:
@@ -82,13 +128,52 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 	case tui.CommitModeMsg:
 		m.Mode = CommitMode
-		return m, nil
+		cmd = m.CommitModel.Update(msg)
+		return m, cmd
+	case tui.CommitFailedMsg:
+		m.CommitModel.Update(msg)
+		cmd = m.StatusModel.Update(tui.RenderErrorMsg(msg.Err))
+		return m, cmd
 	case tui.CommitSuccessMsg:
-		return m, tea.Batch(tui.FetchStatus, tui.StatusMode)
+		m.CommitModel.Update(msg)
+		m.InfoModel.Update(msg)
+		cmd = m.StatusModel.Update(msg)
+		return m, tea.Batch(cmd, tui.FetchStatus, tui.StatusMode)
+	case tui.AmendModeMsg, tui.LastCommitFetchedMsg:
+		cmd = m.CommitModel.Update(msg)
+		return m, cmd
+	case tui.AmendDoneMsg:
+		cmd = m.CommitModel.Update(msg)
+		if msg.Err == nil {
+			cmds = append(cmds, m.StatusModel.Update(msg), status.FetchInfoCmd(m.SvnService))
+		}
+		return m, tea.Batch(append(cmds, cmd)...)
+	case tui.ClearBannerMsg:
+		cmd = m.StatusModel.Update(msg)
+		return m, cmd
 	case tui.QuitMsg:
 		m.CommitModel.SaveDraft()
 		return m, tea.Quit
-	case tui.FetchStatusMsg:
+	case tui.FetchStatusMsg, tui.UpdatedMsg:
+		cmd = m.StatusModel.Update(msg)
+		return m, cmd
+	case tui.InfoFetchedMsg:
+		cmds = append(cmds, m.CommitModel.Update(msg))
+		m.StatusModel.Update(msg)
+		_, cmd = m.InfoModel.Update(msg)
+		return m, tea.Batch(append(cmds, cmd)...)
+	case tui.BugtraqFetchedMsg, tui.UsernameFetchedMsg, tui.LogMessagesFetchedMsg, tui.EditorFinishedMsg:
+		m.CommitModel.Update(msg)
+		return m, nil
+	case tui.SaveDraftMsg, tui.PreflightCheckedMsg, tui.PreflightActionDoneMsg, commit.HooksFinishedMsg:
+		cmd = m.CommitModel.Update(msg)
+		return m, cmd
+	case tui.StatusFetchedMsg:
+		cmds = append(cmds, m.CommitModel.Update(msg))
+		cmds = append(cmds, m.StatusModel.Update(msg))
+		return m, tea.Batch(cmds...)
+	case tui.DiffFetchedMsg, tui.DiffsFetchedMsg:
+		m.CommitModel.Update(msg)
 		cmd = m.StatusModel.Update(msg)
 		return m, cmd
 	case tui.RefreshStatusPanelMsg:
@@ -97,12 +182,21 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tui.RenderErrorMsg:
 		cmd = m.StatusModel.Update(msg)
 		return m, cmd
+	case tui.FilesChangedMsg:
+		cmd = m.StatusModel.Update(msg)
+		return m, tea.Batch(cmd, m.Watcher.Wait())
 	case tea.KeyMsg:
-		keyStr := msg.String()
-		m.Logger.Info(keyStr)
-		switch keyStr {
-		case "ctrl+c":
+		m.Logger.Info(msg.String())
+		switch {
+		case key.Matches(msg, m.Keys.App.Quit):
 			return m, tui.Quit
+		case m.showHelp: // any other key closes the help overlay
+			m.showHelp = false
+			return m, nil
+		case m.Mode == StatusMode && key.Matches(msg, m.Keys.Status.Help),
+			m.Mode == CommitMode && key.Matches(msg, m.Keys.Commit.Help):
+			m.showHelp = true
+			return m, nil
 		default:
 			switch m.Mode {
 			case StatusMode:
@@ -119,17 +213,32 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
+// contextHelp is the help for the mode and cursor element that has the keys.
+func (m *Model) contextHelp() tui.ContextHelp {
+	if m.Mode == CommitMode {
+		return m.CommitModel.Help()
+	}
+	return m.StatusModel.Help()
+}
+
 func (m *Model) View() string {
 	var content string
-	switch m.Mode {
-	case StatusMode:
+	switch {
+	case m.showHelp:
+		// padding left 1 + left and right border
+		overlay := tui.HelpOverlay(m.contextHelp(), m.width-3)
+		if m.Mode == StatusMode {
+			overlay = tui.JoinVerticalStyled(lipgloss.Left, styles.BaseStyle, m.InfoModel.View(), overlay)
+		}
+		content = overlay
+	case m.Mode == StatusMode:
 		content = tui.JoinVerticalStyled(
 			lipgloss.Left,
 			styles.BaseStyle,
 			m.InfoModel.View(),
 			m.StatusModel.View(),
 		)
-	case CommitMode:
+	case m.Mode == CommitMode:
 		/*
 			content = tui.JoinVerticalStyled(
 				lipgloss.Left,
@@ -145,10 +254,15 @@ func (m *Model) View() string {
 		)
 	}
 	m.Logger.Info("App.View()")
-	return styles.BaseStyle.
+	main := styles.BaseStyle.
 		PaddingLeft(1).
 		PaddingTop(1).
 		Width(m.width).
-		Height(m.height).
+		Height(m.height - hintBarHeight).
 		Render(content)
+	hints := styles.BaseStyle.
+		PaddingLeft(1).
+		Width(m.width).
+		Render(tui.HintBar(m.help, m.contextHelp()))
+	return lipgloss.JoinVertical(lipgloss.Left, main, hints)
 }
@@ -0,0 +1,63 @@
+package commit
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/DiwashRai/svnty/svn"
+	"github.com/DiwashRai/svnty/tui"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func FetchLastCommitCmd(ctx context.Context, s svn.Service, author string) tea.Cmd {
+	return func() tea.Msg {
+		entry, err := s.FetchLastCommit(ctx, author)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return tui.RenderErrorMsg(err)
+		}
+		return tui.LastCommitFetchedMsg{Commit: entry}
+	}
+}
+
+func SetLogMessageCmd(s svn.Service, rev uint32, msg string) tea.Cmd {
+	return func() tea.Msg {
+		return tui.AmendDoneMsg{Revision: rev, Message: msg, Err: s.SetLogMessage(rev, msg)}
+	}
+}
+
+func (m *Model) amending() bool {
+	return m.amendRev > 0
+}
+
+// startAmend loads the message of entry into the editor, setting the draft
+// aside until the amend is done or cancelled.
+func (m *Model) startAmend(entry svn.LogEntry) {
+	if !m.amending() {
+		m.amendSaved = m.textarea.Value()
+	}
+	m.amendRev = entry.Revision
+	m.amendErr = nil
+	m.templateIdx = -1
+	m.textarea.SetValue(entry.Message)
+	m.setFocus(focusMessage)
+}
+
+func (m *Model) endAmend() {
+	m.textarea.SetValue(m.amendSaved)
+	m.amendSaved = ""
+	m.amendRev = 0
+	m.amendErr = nil
+}
+
+func (m *Model) submitAmend() tea.Cmd {
+	if HasErrors(m.Linter.Lint(m.message())) {
+		return func() tea.Msg {
+			return tui.RenderErrorMsg(fmt.Errorf("Commit message has lint errors"))
+		}
+	}
+	return SetLogMessageCmd(m.SvnService, m.amendRev, sanitizeMessage(m.message()))
+}
@@ -1,20 +1,28 @@
 package commit
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"log/slog"
 	"regexp"
+	"slices"
 	"strings"
+	"time"
 
+	"github.com/DiwashRai/svnty/status"
 	"github.com/DiwashRai/svnty/styles"
 	"github.com/DiwashRai/svnty/svn"
 	"github.com/DiwashRai/svnty/tui"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 )
 
 const (
@@ -22,10 +30,7 @@ const (
 	commitPanelWidth = 77 // 72 + 4(linenumber gutter) + 1(padding left)
 )
 
-var (
-	border    = lipgloss.RoundedBorder()
-	commitTop = styles.GetBorderTopWithTitle("Commit Message", commitPanelWidth)
-)
+var border = lipgloss.RoundedBorder()
 
 type CommitMode int
 
@@ -34,13 +39,63 @@ const (
 	MsgListMode
 )
 
+// focus is the input that receives typed keys.
+type focus int
+
+const (
+	focusMessage focus = iota
+	focusIssue
+	focusSearch
+)
+
+const (
+	importLogLimit = 200
+	draftInterval  = 10 * time.Second
+)
+
 type Model struct {
 	SvnService    svn.Service
 	Logger        *slog.Logger
+	Author        string // svn username, asked from svn when empty
+	username      string // the one svn has cached for the repository
+	PageSize      int    // rows the staged preview scrolls by
+	Keys          tui.KeyMap
 	textarea      textarea.Model
 	msglist       list.Model
 	Mode          CommitMode
 	CommitHistory svn.CommitHistory
+	Draft         svn.Draft
+	Staged        []svn.PathStatus
+	Linter        Linter
+	Templates     []Template
+	Info          svn.RepoInfo
+	Bugtraq       svn.Bugtraq
+	templateIdx   int
+	issueInput    textinput.Model
+	searchInput   textinput.Model
+	focus         focus
+	preview       preview
+	problems      []svn.PreflightProblem
+	preflightErr  error
+	preflightBusy bool
+	commitSeq     int  // ID of the last commit submitted
+	pendingID     int  // ID of the commit in progress, 0 when there is none
+	committing    bool // svn commit is running for pendingID
+	Hooks         HooksConfig
+	hooksRunning  bool
+	cancelHooks   context.CancelFunc // kills the running hooks
+	cancelLog     context.CancelFunc // kills the running svn log search
+	hookResults   []HookResult
+	hookOutput    viewport.Model
+	hookMessage   string
+	amendRev      uint32 // revision whose message is being amended, 0 when committing
+	amendSaved    string // the draft set aside while amending
+	amendErr      error
+	Options       *svn.CommitOptions // shared with the commit menu of the status panel
+	showOptions   bool
+	optionsMenu   tui.Menu
+	Width         int
+	Height        int
 }
 
 type ItemType struct {
@@ -51,23 +106,16 @@ type ItemType struct {
 func (i ItemType) FilterValue() string { return i.FullMessage }
 
 func NewItem(msg string) list.Item {
-	displayText := strings.ReplaceAll(msg, "\n", " ")
-	if len(displayText) > maxDisplayLength {
-		displayText = displayText[:maxDisplayLength-3] + "..."
-	}
+	displayText := tui.Truncate(strings.ReplaceAll(msg, "\n", " "), maxDisplayLength, "...")
 	return ItemType{
 		DisplayText: displayText,
 		FullMessage: msg,
 	}
 }
 
-type itemDelegate struct{}
-
-var (
-	itemStyle         = styles.BaseStyle.PaddingLeft(4)
-	selectedItemStyle = styles.BaseStyle.PaddingLeft(2).
-				Foreground(lipgloss.Color(styles.CommitListSelColor))
-)
+type itemDelegate struct {
+	bugtraq svn.Bugtraq
+}
 
 func (d itemDelegate) Height() int  { return 1 }
 func (d itemDelegate) Spacing() int { return 0 }
@@ -80,7 +128,12 @@ func (d itemDelegate) Render(w io.Writer, m list.Model, index int, item list.Ite
 		return
 	}
 
-	str := fmt.Sprintf("%d. %s", index+1, li.DisplayText)
+	str := fmt.Sprintf("%d. %s", index+1, d.linkIssues(li.DisplayText))
+
+	// built here rather than once, as the theme is applied after init
+	itemStyle := styles.BaseStyle.PaddingLeft(4)
+	selectedItemStyle := styles.BaseStyle.PaddingLeft(2).
+		Foreground(lipgloss.Color(styles.CommitListSelColor))
 
 	fn := itemStyle.Render
 	if index == m.Index() {
@@ -90,10 +143,35 @@ func (d itemDelegate) Render(w io.Writer, m list.Model, index int, item list.Ite
 	}
 
 	displayStr := fn(str)
-	displayStr = displayStr[:len(displayStr)-4] // remove the resetAll terminal code
+	displayStr = strings.TrimSuffix(displayStr, "\x1b[0m") // remove the resetAll terminal code
 	fmt.Fprint(w, displayStr)
 }
 
+// linkIssues turns the issue IDs in msg into terminal hyperlinks to the
+// bugtraq:url of the repository.
+func (d itemDelegate) linkIssues(msg string) string {
+	if d.bugtraq.URL == "" {
+		return msg
+	}
+
+	var (
+		b    strings.Builder
+		last int
+	)
+	for _, ref := range d.bugtraq.IssueRefs(msg) {
+		if ref.Start < last {
+			continue
+		}
+		b.WriteString(msg[last:ref.Start])
+		b.WriteString(ansi.SetHyperlink(d.bugtraq.IssueURL(ref.ID)))
+		b.WriteString(ref.ID)
+		b.WriteString(ansi.ResetHyperlink())
+		last = ref.End
+	}
+	b.WriteString(msg[last:])
+	return b.String()
+}
+
 func (m *Model) buildHistoryItems() []list.Item {
 	items := []list.Item{}
 	for _, msg := range m.CommitHistory.GetHistory() {
@@ -103,6 +181,10 @@ func (m *Model) buildHistoryItems() []list.Item {
 }
 
 func (m *Model) Init() tea.Cmd {
+	if m.Options == nil {
+		m.Options = &svn.CommitOptions{}
+	}
+
 	ti := textarea.New()
 	ti.ShowLineNumbers = true
 	ti.Prompt = ""
@@ -110,15 +192,23 @@ func (m *Model) Init() tea.Cmd {
 
 	ti.SetWidth(commitPanelWidth)
 	ti.SetHeight(8)
+	ti.CharLimit = 0 // long messages and restored drafts must not be cut off
 	ti.Focus()
+	ti.SetValue(m.Draft.Message)
 	m.textarea = ti
 
 	historyItems := m.buildHistoryItems()
-	historyList := list.New(historyItems, itemDelegate{}, 10, 40)
+	historyList := list.New(historyItems, itemDelegate{bugtraq: m.Bugtraq}, 10, 40)
 	historyList.SetShowTitle(false)
 	historyList.SetShowStatusBar(false)
 	historyList.SetShowHelp(false)
+	historyList.SetShowFilter(false)
 	m.msglist = historyList
+	m.templateIdx = -1
+
+	m.issueInput = newInput("")
+	m.searchInput = newInput("/")
+	m.preview = newPreview()
 
 	return nil
 }
@@ -128,24 +218,185 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 	var cmd tea.Cmd
 
 	switch msg := msg.(type) {
+	case tui.InfoFetchedMsg:
+		m.Info = msg.Info
+		m.CommitHistory.UseRepository(msg.Info.UUID)
+		m.msglist.SetItems(m.buildHistoryItems())
+		if m.Author == "" && m.username == "" {
+			return FetchUsernameCmd(m.SvnService, msg.Info.RemoteURL)
+		}
+	case tui.UsernameFetchedMsg:
+		m.username = msg.Username
+	case tui.LogMessagesFetchedMsg:
+		m.stopLogSearch()
+		added := m.CommitHistory.ImportMessages(msg.Messages)
+		m.Logger.Info("Imported log messages", "count", added)
+		m.CommitHistory.SaveToFile()
+		m.msglist.SetItems(m.buildHistoryItems())
+		m.filterHistory()
+	case tea.WindowSizeMsg:
+		m.Width, m.Height = msg.Width, msg.Height
+	case tui.StatusFetchedMsg:
+		m.Staged = msg.Status.Staged().Paths
+		m.preview.setFiles(m.Staged)
+		return status.FetchDiffsCmd(m.SvnService, m.preview.paths())
+	case tui.DiffFetchedMsg:
+		m.preview.addDiffs(map[string][]string{msg.Path: msg.Lines})
+	case tui.DiffsFetchedMsg:
+		m.preview.addDiffs(msg.Diffs)
+	case tui.BugtraqFetchedMsg:
+		m.Bugtraq = msg.Bugtraq
+		m.msglist.SetDelegate(itemDelegate{bugtraq: msg.Bugtraq})
+	case tui.CommitModeMsg:
+		if m.textarea.Value() == "" && len(m.Templates) > 0 {
+			m.applyTemplate(0)
+		}
+		return status.FetchDiffsCmd(m.SvnService, m.preview.paths())
+	case tui.AmendModeMsg:
+		return FetchLastCommitCmd(m.searchLog(), m.SvnService, m.author())
+	case tui.LastCommitFetchedMsg:
+		m.stopLogSearch()
+		m.startAmend(msg.Commit)
+		return tui.CommitMode
+	case tui.AmendDoneMsg:
+		if msg.Err != nil {
+			m.amendErr = msg.Err
+			return nil
+		}
+		m.CommitHistory.AddMessage(msg.Message)
+		m.CommitHistory.SaveToFile()
+		m.msglist.SetItems(m.buildHistoryItems())
+		m.filterHistory()
+		m.endAmend()
+		return tui.StatusMode
+	case tui.EditorFinishedMsg:
+		m.textarea.SetValue(msg.Message)
+		m.setFocus(focusMessage)
+	case tui.PreflightCheckedMsg:
+		if msg.ID != m.pendingID {
+			return nil
+		}
+		switch {
+		case msg.Err != nil:
+			m.problems = []svn.PreflightProblem{}
+			m.preflightErr = msg.Err
+		case len(msg.Problems) == 0:
+			return m.runHooks(msg.Message)
+		default:
+			m.problems = msg.Problems
+		}
+	case HooksFinishedMsg:
+		if msg.ID != m.pendingID {
+			return nil
+		}
+		m.stopHooks()
+		if !hooksFailed(msg.Results) {
+			return m.commit(msg.Message)
+		}
+		m.showHookResults(msg.Message, msg.Results)
+	case tui.PreflightActionDoneMsg:
+		m.preflightBusy = false
+		if msg.Err != nil {
+			m.preflightErr = msg.Err
+			return nil
+		}
+		m.closePreflight()
+		return tui.FetchStatus
+	case tui.SaveDraftMsg:
+		m.SaveDraft()
+		return DraftTick()
+	case tui.CommitFailedMsg:
+		if msg.ID == m.pendingID {
+			m.pendingID, m.committing = 0, false
+		}
+	case tui.CommitSuccessMsg:
+		m.pendingID, m.committing = 0, false
+		m.Draft.Clear()
+		m.CommitHistory.AddMessage(sanitizeMessage(msg.Message))
+		m.CommitHistory.SaveToFile()
+		m.msglist.SetItems(m.buildHistoryItems())
+		m.filterHistory()
+		m.textarea.SetValue("")
+		m.issueInput.SetValue("")
+		m.templateIdx = -1
 	case tea.KeyMsg:
-		keyStr := msg.String()
-		switch keyStr {
-		case "esc":
+		if m.problems != nil {
+			if m.preflightBusy {
+				return nil
+			}
+			return m.updatePreflight(msg)
+		}
+		if m.hookResults != nil {
+			return m.updateHooks(msg)
+		}
+		if m.showOptions {
+			return m.updateOptions(msg)
+		}
+		if m.focus == focusSearch {
+			return m.updateSearch(msg)
+		}
+
+		keys := m.Keys.Commit
+		switch {
+		case key.Matches(msg, keys.Cancel):
+			m.cancelPending()
+			m.stopLogSearch()
+			if m.amending() {
+				m.endAmend()
+			}
 			return tui.StatusMode
-		case "ctrl+d": // [d]o Commit
+		case key.Matches(msg, keys.Submit): // [d]o Commit
+			if m.pendingID != 0 {
+				return nil
+			}
+			if m.amending() {
+				return m.submitAmend()
+			}
 			return m.Submit()
-		case "up", "ctrl+k":
+		case key.Matches(msg, keys.HistoryUp):
 			m.msglist.CursorUp()
-		case "down", "ctrl+j":
+		case key.Matches(msg, keys.HistoryDown):
 			m.msglist.CursorDown()
-		case "tab":
+		case key.Matches(msg, keys.InsertHistory):
 			if selectedItem, ok := m.msglist.SelectedItem().(ItemType); ok {
 				m.textarea.SetValue(m.textarea.Value() + selectedItem.FullMessage)
 			}
-		case "ctrl+r": // [r]eset message
+		case key.Matches(msg, keys.Reset): // [r]eset message
 			m.textarea.SetValue("")
+			m.templateIdx = -1
+		case key.Matches(msg, keys.Template): // cycle [t]emplates
+			if len(m.Templates) > 0 {
+				m.applyTemplate((m.templateIdx + 1) % len(m.Templates))
+			}
+		case key.Matches(msg, keys.PreviewUp): // staged files preview
+			m.preview.moveCursor(-1)
+		case key.Matches(msg, keys.PreviewDown):
+			m.preview.moveCursor(1)
+		case key.Matches(msg, keys.PreviewToggle):
+			m.preview.toggleExpanded()
+		case key.Matches(msg, keys.PreviewPgUp):
+			m.preview.scroll(-m.PageSize)
+		case key.Matches(msg, keys.PreviewPgDown):
+			m.preview.scroll(m.PageSize)
+		case key.Matches(msg, keys.IssueField): // switch to [b]ug ID field
+			if m.Bugtraq.HasIssueField() && m.focus == focusIssue {
+				m.setFocus(focusMessage)
+			} else if m.Bugtraq.HasIssueField() {
+				m.setFocus(focusIssue)
+			}
+		case key.Matches(msg, keys.Options): // commit options
+			m.openOptions()
+		case key.Matches(msg, keys.Search): // [f]ilter history
+			m.setFocus(focusSearch)
+		case key.Matches(msg, keys.Editor): // [o]pen in $EDITOR
+			return EditInEditorCmd(m.textarea.Value(), m.Info, m.Staged)
+		case key.Matches(msg, keys.ImportLog): // import history from svn [l]og
+			return ImportLogCmd(m.searchLog(), m.SvnService, m.author())
 		default:
+			if m.focus == focusIssue {
+				m.issueInput, cmd = m.issueInput.Update(msg)
+				return cmd
+			}
 			m.textarea, cmd = m.textarea.Update(msg)
 			return cmd
 		}
@@ -154,7 +405,73 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 	return nil
 }
 
+// updateSearch handles keys while the history search query has focus. enter
+// keeps the filter, esc clears it.
+func (m *Model) updateSearch(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, m.Keys.Search.Clear):
+		m.searchInput.SetValue("")
+		m.filterHistory()
+		m.setFocus(focusMessage)
+	case key.Matches(msg, m.Keys.Search.Accept):
+		m.setFocus(focusMessage)
+	case key.Matches(msg, m.Keys.Commit.HistoryUp):
+		m.msglist.CursorUp()
+	case key.Matches(msg, m.Keys.Commit.HistoryDown):
+		m.msglist.CursorDown()
+	default:
+		var cmd tea.Cmd
+		m.searchInput, cmd = m.searchInput.Update(msg)
+		m.filterHistory()
+		return cmd
+	}
+	return nil
+}
+
+// filterHistory fuzzy filters the history list by the search query.
+func (m *Model) filterHistory() {
+	if query := m.searchInput.Value(); query != "" {
+		m.msglist.SetFilterText(query)
+	} else {
+		m.msglist.ResetFilter()
+	}
+}
+
+func (m *Model) setFocus(f focus) {
+	m.focus = f
+	m.textarea.Blur()
+	m.issueInput.Blur()
+	m.searchInput.Blur()
+
+	switch f {
+	case focusMessage:
+		m.textarea.Focus()
+	case focusIssue:
+		m.issueInput.Focus()
+	case focusSearch:
+		m.searchInput.Focus()
+	}
+}
+
+// message returns the commit message with the bugtraq issue line appended.
+func (m *Model) message() string {
+	return m.Bugtraq.AppendIssue(m.textarea.Value(), m.issueInput.Value())
+}
+
+func (m *Model) applyTemplate(idx int) {
+	m.templateIdx = idx
+	m.textarea.SetValue(m.Templates[idx].Expand(m.Info, m.Staged, m.Linter, m.author()))
+}
+
 func (m *Model) View() string {
+	top := styles.GetBorderTopWithTitle("Commit Message", commitPanelWidth)
+	if m.amending() {
+		top = styles.GetBorderTopWithTitle(fmt.Sprintf("Amend Message of r%d", m.amendRev), commitPanelWidth)
+	} else if m.templateIdx >= 0 {
+		title := fmt.Sprintf("Commit Message (%s)", m.Templates[m.templateIdx].Name)
+		top = styles.GetBorderTopWithTitle(title, commitPanelWidth)
+	}
+
 	commitPanel := styles.BorderStyle.
 		BorderTop(false).
 		BorderLeft(true).
@@ -162,17 +479,94 @@ func (m *Model) View() string {
 		BorderBottom(true).
 		Render(m.textarea.View())
 
+	var issueField string
+	if m.Bugtraq.HasIssueField() {
+		labelStyle := styles.Comment
+		if m.focus == focusIssue {
+			labelStyle = styles.LintWarningStyle
+		}
+		issueField = lipgloss.JoinVertical(
+			lipgloss.Left,
+			labelStyle.Render(" "+m.Bugtraq.FieldLabel()),
+			styles.BaseStyle.PaddingLeft(1).Render(m.issueInput.View()),
+		)
+	}
+
 	var historyList string
 	if len(m.msglist.Items()) > 0 {
 		historyList = m.msglist.View()
+		if m.focus == focusSearch || m.searchInput.Value() != "" {
+			historyList = lipgloss.JoinVertical(
+				lipgloss.Left,
+				styles.BaseStyle.PaddingLeft(1).Render(m.searchInput.View()),
+				historyList,
+			)
+		}
 	}
 
-	return lipgloss.JoinVertical(
-		lipgloss.Left,
-		commitTop,
-		commitPanel,
-		historyList,
-	)
+	var amendErr string
+	if m.amendErr != nil {
+		amendErr = styles.LintErrorStyle.Render(tui.Truncate(" "+m.amendErr.Error(), commitPanelWidth, "…"))
+	}
+
+	parts := []string{top, commitPanel, issueField, amendErr, m.optionsSummary(), m.lintView(), historyList}
+	switch {
+	case m.showOptions:
+		parts = []string{top, commitPanel, issueField, m.optionsView()}
+	case m.problems != nil:
+		parts = []string{top, commitPanel, issueField, m.preflightView()}
+	case m.hookResults != nil:
+		parts = []string{top, commitPanel, issueField, m.hooksView()}
+	case m.committing:
+		parts = []string{top, commitPanel, issueField, styles.Comment.Render(" Committing..."), historyList}
+	case m.hooksRunning:
+		parts = []string{top, commitPanel, issueField, styles.Comment.Render(" Running pre-commit hooks..."), historyList}
+	case m.pendingID != 0:
+		parts = []string{top, commitPanel, issueField, styles.Comment.Render(" Checking staged paths..."), historyList}
+	}
+	// empty parts would otherwise add blank lines
+	parts = slices.DeleteFunc(parts, func(s string) bool { return s == "" })
+	editor := lipgloss.JoinVertical(lipgloss.Left, parts...)
+
+	// padding on left and top is 1
+	width, height := m.Width-1, m.Height-1
+	editorWidth := lipgloss.Width(editor)
+	if previewWidth := width - editorWidth - 1; previewWidth >= minPreviewWidth {
+		return tui.JoinHorizontalStyled(
+			lipgloss.Top,
+			styles.BaseStyle,
+			editor,
+			" ",
+			m.preview.view(m.Info, previewWidth, height),
+		)
+	}
+	if previewHeight := height - lipgloss.Height(editor); previewHeight >= minPreviewHeight {
+		return tui.JoinVerticalStyled(
+			lipgloss.Left,
+			styles.BaseStyle,
+			editor,
+			m.preview.view(m.Info, min(width, commitPanelWidth+2), previewHeight),
+		)
+	}
+	return editor
+}
+
+func (m *Model) lintView() string {
+	violations := m.Linter.Lint(m.message())
+	if len(violations) == 0 {
+		return ""
+	}
+
+	lines := make([]string, 0, len(violations))
+	for _, v := range violations {
+		style, label := styles.LintWarningStyle, "warning"
+		if v.Severity == SeverityError {
+			style, label = styles.LintErrorStyle, "error"
+		}
+		line := fmt.Sprintf(" %s: %s (%s)", label, v.Message, v.Rule)
+		lines = append(lines, style.Render(tui.Truncate(line, commitPanelWidth, "…")))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
 }
 
 func sanitizeMessage(msg string) string {
@@ -187,33 +581,135 @@ func sanitizeMessage(msg string) string {
 	return strings.TrimSpace(msg)
 }
 
-func CommitStagedCmd(m *Model) tea.Cmd {
+func newInput(prompt string) textinput.Model {
+	ti := textinput.New()
+	ti.Prompt = prompt
+	ti.PromptStyle = styles.Comment
+	ti.TextStyle = styles.BaseStyle
+	ti.Cursor.Style = styles.BaseStyle
+	ti.Width = commitPanelWidth
+	return ti
+}
+
+func ImportLogCmd(ctx context.Context, s svn.Service, author string) tea.Cmd {
 	return func() tea.Msg {
-		err := m.SvnService.CommitStaged(m.textarea.Value())
+		msgs, err := s.FetchLogMessages(ctx, author, importLogLimit)
+		if ctx.Err() != nil {
+			return nil
+		}
 		if err != nil {
 			return tui.RenderErrorMsg(err)
 		}
+		return tui.LogMessagesFetchedMsg{Messages: msgs}
+	}
+}
 
-		sanitizedMsg := sanitizeMessage(m.textarea.Value())
-		m.CommitHistory.AddMessage(sanitizedMsg)
-		m.CommitHistory.SaveToFile()
-		m.textarea.SetValue("")
-		return tui.CommitSuccessMsg{}
+func FetchUsernameCmd(s svn.Service, repoURL string) tea.Cmd {
+	return func() tea.Msg {
+		username, err := s.FetchUsername(repoURL)
+		if err != nil {
+			return tui.RenderErrorMsg(err)
+		}
+		return tui.UsernameFetchedMsg{Username: username}
+	}
+}
+
+func FetchBugtraqCmd(s svn.Service) tea.Cmd {
+	return func() tea.Msg {
+		bugtraq, err := s.FetchBugtraq()
+		if err != nil {
+			return tui.RenderErrorMsg(err)
+		}
+		return tui.BugtraqFetchedMsg{Bugtraq: bugtraq}
+	}
+}
+
+func CommitStagedCmd(s svn.Service, id int, msg string, opts svn.CommitOptions) tea.Cmd {
+	return func() tea.Msg {
+		rev, err := s.CommitStaged(msg, opts)
+		if err != nil {
+			return tui.CommitFailedMsg{ID: id, Err: err}
+		}
+		return tui.CommitSuccessMsg{Message: msg, Revision: rev}
 	}
 }
 
+// Submit starts a commit of the staged paths: a pre-flight check, then the
+// hooks, then svn commit. Only one commit can be in progress at a time.
 func (m *Model) Submit() tea.Cmd {
-	return CommitStagedCmd(m)
+	if m.pendingID != 0 {
+		return nil
+	}
+	if len(m.Staged) == 0 {
+		return func() tea.Msg {
+			return tui.RenderErrorMsg(fmt.Errorf("No files staged to commit"))
+		}
+	}
+	if HasErrors(m.Linter.Lint(m.message())) {
+		return func() tea.Msg {
+			return tui.RenderErrorMsg(fmt.Errorf("Commit message has lint errors"))
+		}
+	}
+	m.commitSeq++
+	m.pendingID = m.commitSeq
+	return PreflightCmd(m.SvnService, m.pendingID, m.message())
+}
+
+// commit runs svn commit for the pending commit. It can no longer be
+// cancelled from here on.
+func (m *Model) commit(msg string) tea.Cmd {
+	m.committing = true
+	return CommitStagedCmd(m.SvnService, m.pendingID, msg, *m.Options)
+}
+
+// cancelPending forgets the commit in progress, so the results of its
+// pre-flight check and hooks are dropped when they arrive, and kills its
+// hooks.
+func (m *Model) cancelPending() {
+	if m.committing {
+		return
+	}
+	m.pendingID = 0
+	m.stopHooks()
+}
+
+func (m *Model) stopHooks() {
+	if m.cancelHooks != nil {
+		m.cancelHooks()
+		m.cancelHooks = nil
+	}
+	m.hooksRunning = false
+}
+
+// searchLog returns the context of a new svn log search, killing the one
+// still running so only the latest result arrives.
+func (m *Model) searchLog() context.Context {
+	m.stopLogSearch()
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancelLog = cancel
+	return ctx
+}
+
+func (m *Model) stopLogSearch() {
+	if m.cancelLog != nil {
+		m.cancelLog()
+		m.cancelLog = nil
+	}
 }
 
 func (m *Model) SaveDraft() {
-	draftMsg := sanitizeMessage(m.textarea.Value())
-	if len(draftMsg) == 0 {
+	if m.amending() {
+		m.Draft.Save(sanitizeMessage(m.amendSaved))
 		return
 	}
+	m.Draft.Save(sanitizeMessage(m.textarea.Value()))
+}
 
-	m.CommitHistory.AddMessage(draftMsg)
-	m.CommitHistory.SaveToFile()
+// DraftTick saves the draft every draftInterval so a crash loses little.
+func DraftTick() tea.Cmd {
+	return tea.Tick(draftInterval, func(time.Time) tea.Msg {
+		return tui.SaveDraftMsg{}
+	})
 }
 
 func getTextAreaStyle() (textarea.Style, textarea.Style) {
@@ -0,0 +1,110 @@
+package commit
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/DiwashRai/svnty/svn"
+	"github.com/DiwashRai/svnty/tui"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestNewItemTruncatesJapaneseOnCellBoundary(t *testing.T) {
+	// 40 wide runes take 80 cells, more than maxDisplayLength
+	msg := strings.Repeat("修正", 20) + "\n詳細"
+	item := NewItem(msg).(ItemType)
+
+	if w := ansi.StringWidth(item.DisplayText); w > maxDisplayLength {
+		t.Errorf("DisplayText is %d cells wide, limit %d", w, maxDisplayLength)
+	}
+	if !strings.HasSuffix(item.DisplayText, "...") {
+		t.Errorf("DisplayText %q does not end with the tail", item.DisplayText)
+	}
+	// 72 cells less the 3 cell tail leaves room for 34 whole wide runes
+	if want := strings.Repeat("修正", 17) + "..."; item.DisplayText != want {
+		t.Errorf("DisplayText = %q, want %q", item.DisplayText, want)
+	}
+	if item.FullMessage != msg {
+		t.Errorf("FullMessage = %q, want the message unchanged", item.FullMessage)
+	}
+}
+
+func TestNewItemKeepsShortJapaneseMessage(t *testing.T) {
+	item := NewItem("バグを修正\n本文").(ItemType)
+	if want := "バグを修正 本文"; item.DisplayText != want {
+		t.Errorf("DisplayText = %q, want %q", item.DisplayText, want)
+	}
+}
+
+func newTestModel() *Model {
+	m := &Model{
+		SvnService: &svn.MockService{},
+		Logger:     slog.New(slog.DiscardHandler),
+		Keys:       tui.DefaultKeyMap(),
+		Staged:     []svn.PathStatus{{Path: "main.go"}},
+	}
+	m.Init()
+	m.textarea.SetValue("Fix the build")
+	return m
+}
+
+func TestStalePreflightIgnoredAfterCancel(t *testing.T) {
+	m := newTestModel()
+	if m.Submit() == nil {
+		t.Fatal("Submit() started nothing")
+	}
+	id := m.pendingID
+	if m.Submit() != nil {
+		t.Error("Submit() started a second commit while one was pending")
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m.Update(tui.PreflightCheckedMsg{ID: id, Problems: []svn.PreflightProblem{{Path: "main.go"}}})
+	if m.problems != nil {
+		t.Error("a pre-flight result of a cancelled commit was shown")
+	}
+	if m.Submit() == nil {
+		t.Error("Submit() is still blocked after cancelling")
+	}
+}
+
+func TestPreflightErrorShownInPanel(t *testing.T) {
+	m := newTestModel()
+	m.Submit()
+	m.Update(tui.PreflightCheckedMsg{ID: m.pendingID, Err: errors.New("E170013: Unable to connect")})
+	if m.problems == nil || m.preflightErr == nil {
+		t.Fatal("the pre-flight error did not open the panel")
+	}
+	if view := ansi.Strip(m.preflightView()); !strings.Contains(view, "E170013") {
+		t.Errorf("the panel does not show the error:\n%s", view)
+	}
+}
+
+// logService blocks in svn log until the search is cancelled.
+type logService struct {
+	svn.MockService
+}
+
+func (s *logService) FetchLogMessages(ctx context.Context, author string, limit int) ([]string, error) {
+	<-ctx.Done()
+	return nil, errors.New("signal: killed")
+}
+
+func TestLeavingCommitModeKillsLogSearch(t *testing.T) {
+	m := newTestModel()
+	m.SvnService = &logService{}
+	search := m.Update(tea.KeyMsg{Type: tea.KeyCtrlL})
+	if search == nil {
+		t.Fatal("import log key started nothing")
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if msg := search(); msg != nil {
+		t.Errorf("cancelled search returned %#v, want nothing", msg)
+	}
+}
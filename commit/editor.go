@@ -0,0 +1,74 @@
+package commit
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/DiwashRai/svnty/svn"
+	"github.com/DiwashRai/svnty/tui"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// same marker svn itself puts in the editor template
+const editorMarker = "--This line, and those below, will be ignored--"
+
+func editorCommand() []string {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if fields := strings.Fields(os.Getenv(env)); len(fields) > 0 {
+			return fields
+		}
+	}
+	return []string{"vi"}
+}
+
+// EditInEditorCmd suspends the program and opens msg in $VISUAL or $EDITOR.
+// The staged files are listed below the marker line and stripped again when
+// the editor exits.
+func EditInEditorCmd(msg string, info svn.RepoInfo, staged []svn.PathStatus) tea.Cmd {
+	f, err := os.CreateTemp("", "svnty-commit-*.txt")
+	if err != nil {
+		return func() tea.Msg {
+			return tui.RenderErrorMsg(fmt.Errorf("failed to create temp file: %w", err))
+		}
+	}
+	defer f.Close()
+
+	var b strings.Builder
+	b.WriteString(msg)
+	b.WriteString("\n" + editorMarker + "\n\n")
+	for _, ps := range staged {
+		fmt.Fprintf(&b, "%c    %s\n", ps.Status, displayPath(info, ps.Path))
+	}
+	if _, err := f.WriteString(b.String()); err != nil {
+		os.Remove(f.Name())
+		return func() tea.Msg {
+			return tui.RenderErrorMsg(fmt.Errorf("failed to write temp file: %w", err))
+		}
+	}
+
+	args := editorCommand()
+	cmd := exec.Command(args[0], append(args[1:], f.Name())...)
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		defer os.Remove(f.Name())
+		if err != nil {
+			return tui.RenderErrorMsg(fmt.Errorf("editor %s failed: %w", args[0], err))
+		}
+
+		data, err := os.ReadFile(f.Name())
+		if err != nil {
+			return tui.RenderErrorMsg(fmt.Errorf("failed to read edited message: %w", err))
+		}
+		return tui.EditorFinishedMsg{Message: stripEditorMarker(string(data))}
+	})
+}
+
+func stripEditorMarker(msg string) string {
+	msg = strings.ReplaceAll(msg, "\r\n", "\n")
+	if i := strings.Index(msg, editorMarker); i >= 0 {
+		msg = msg[:i]
+	}
+	return strings.TrimRight(msg, "\n")
+}
@@ -0,0 +1,49 @@
+package commit
+
+import (
+	"github.com/DiwashRai/svnty/tui"
+
+	"github.com/charmbracelet/bubbles/key"
+)
+
+// Help lists the bindings of the popup or input that currently has the keys,
+// in the same order Update checks them.
+func (m *Model) Help() tui.ContextHelp {
+	k := m.Keys
+	switch {
+	case m.problems != nil:
+		bindings := []key.Binding{k.Preflight.Update, k.Preflight.Resolve, k.Preflight.Cancel}
+		return tui.ContextHelp{Title: "pre-flight check", Short: bindings, Full: [][]key.Binding{bindings}}
+	case m.hookResults != nil:
+		bindings := []key.Binding{k.Hooks.Force, k.Hooks.Cancel}
+		return tui.ContextHelp{Title: "pre-commit hooks", Short: bindings, Full: [][]key.Binding{bindings}}
+	case m.showOptions:
+		return m.optionsMenu.Help()
+	case m.focus == focusSearch:
+		bindings := []key.Binding{k.Search.Accept, k.Search.Clear, k.Commit.HistoryUp, k.Commit.HistoryDown}
+		return tui.ContextHelp{Title: "history search", Short: bindings, Full: [][]key.Binding{bindings}}
+	}
+
+	c := k.Commit
+	submit := c.Submit
+	title := "commit editor"
+	if m.amending() {
+		submit.SetHelp(submit.Help().Key, "amend")
+		title = "amend editor"
+	}
+
+	editing := []key.Binding{submit, c.Cancel, c.Reset, c.Template, c.Editor, c.Options}
+	if m.Bugtraq.HasIssueField() {
+		editing = append(editing, c.IssueField)
+	}
+
+	return tui.ContextHelp{
+		Title: title,
+		Short: []key.Binding{submit, c.Cancel, c.Editor, c.Help},
+		Full: [][]key.Binding{
+			editing,
+			{c.HistoryUp, c.HistoryDown, c.InsertHistory, c.Search, c.ImportLog},
+			{c.PreviewUp, c.PreviewDown, c.PreviewToggle, c.PreviewPgUp, c.PreviewPgDown},
+		},
+	}
+}
@@ -0,0 +1,248 @@
+package commit
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/DiwashRai/svnty/config"
+	"github.com/DiwashRai/svnty/styles"
+	"github.com/DiwashRai/svnty/tui"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	HooksFileName      = "hooks.json"
+	hooksViewHeight    = 15
+	defaultHookTimeout = time.Minute
+	hookWaitDelay      = 2 * time.Second // for children of a killed hook to let go of its output
+)
+
+// Hook is a shell command run before committing. It gets the staged files on
+// stdin, one per line, and the message in $SVNTY_COMMIT_MESSAGE and the file
+// named by $SVNTY_COMMIT_MESSAGE_FILE.
+type Hook struct {
+	Name    string `json:"name"`
+	Command string `json:"command"`
+}
+
+// HooksConfig is read from HooksFileName in the svnty config dir. Global
+// hooks run first, followed by the hooks of the repository UUID.
+type HooksConfig struct {
+	Global       []Hook            `json:"global"`
+	Repositories map[string][]Hook `json:"repositories"`
+	Timeout      int               `json:"timeout"` // seconds each hook may run, defaultHookTimeout when 0
+}
+
+func (hc HooksConfig) timeout() time.Duration {
+	if hc.Timeout <= 0 {
+		return defaultHookTimeout
+	}
+	return time.Duration(hc.Timeout) * time.Second
+}
+
+func (hc HooksConfig) HooksFor(uuid string) []Hook {
+	return append(append([]Hook{}, hc.Global...), hc.Repositories[uuid]...)
+}
+
+func LoadHooks(logger *slog.Logger) HooksConfig {
+	var hc HooksConfig
+	if _, err := config.ReadFile(logger, HooksFileName, &hc); err != nil {
+		return HooksConfig{}
+	}
+	return hc
+}
+
+type HookResult struct {
+	Name     string
+	Output   string
+	ExitCode int
+	Err      error // the hook could not be run at all
+}
+
+// HooksFinishedMsg lives here rather than in tui, which cannot import the
+// HookResult type.
+type HooksFinishedMsg struct {
+	ID      int // of the commit they were run for
+	Message string
+	Results []HookResult
+}
+
+func (r HookResult) Failed() bool {
+	return r.Err != nil || r.ExitCode != 0
+}
+
+func shellCommand(ctx context.Context, command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", command)
+	}
+	return exec.CommandContext(ctx, "sh", "-c", command)
+}
+
+// runHook runs hook, killing it after timeout or once ctx is cancelled.
+func runHook(ctx context.Context, timeout time.Duration, hook Hook, dir, msgFile, msg string, files []string) HookResult {
+	result := HookResult{Name: hook.Name}
+	if result.Name == "" {
+		result.Name = hook.Command
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var out bytes.Buffer
+	cmd := shellCommand(ctx, hook.Command)
+	cmd.WaitDelay = hookWaitDelay
+	cmd.Dir = dir
+	cmd.Stdin = strings.NewReader(strings.Join(files, "\n") + "\n")
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	cmd.Env = append(os.Environ(),
+		"SVNTY_COMMIT_MESSAGE="+msg,
+		"SVNTY_COMMIT_MESSAGE_FILE="+msgFile,
+	)
+
+	err := cmd.Run()
+	result.Output = out.String()
+
+	var exitErr *exec.ExitError
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		result.Err = fmt.Errorf("timed out after %s", timeout)
+	case errors.As(err, &exitErr):
+		result.ExitCode = exitErr.ExitCode()
+	case err != nil:
+		result.Err = err
+	}
+	return result
+}
+
+// RunHooksCmd runs every hook in order and reports all of their results, even
+// after one fails, so the whole picture shows up at once. Cancelling ctx kills
+// the running hook and skips the rest.
+func RunHooksCmd(ctx context.Context, hooks []Hook, timeout time.Duration, id int, dir, msg string, files []string) tea.Cmd {
+	return func() tea.Msg {
+		f, err := os.CreateTemp("", "svnty-message-*.txt")
+		if err != nil {
+			err = fmt.Errorf("failed to create temp file: %w", err)
+			return HooksFinishedMsg{ID: id, Message: msg, Results: []HookResult{{Name: "svnty", Err: err}}}
+		}
+		defer os.Remove(f.Name())
+		_, err = f.WriteString(msg)
+		f.Close()
+		if err != nil {
+			err = fmt.Errorf("failed to write temp file: %w", err)
+			return HooksFinishedMsg{ID: id, Message: msg, Results: []HookResult{{Name: "svnty", Err: err}}}
+		}
+
+		results := make([]HookResult, 0, len(hooks))
+		for _, hook := range hooks {
+			if ctx.Err() != nil {
+				break
+			}
+			results = append(results, runHook(ctx, timeout, hook, dir, f.Name(), msg, files))
+		}
+		return HooksFinishedMsg{ID: id, Message: msg, Results: results}
+	}
+}
+
+func hooksFailed(results []HookResult) bool {
+	for _, r := range results {
+		if r.Failed() {
+			return true
+		}
+	}
+	return false
+}
+
+// runHooks starts the configured hooks for msg, or commits straight away when
+// there are none.
+func (m *Model) runHooks(msg string) tea.Cmd {
+	hooks := m.Hooks.HooksFor(m.Info.UUID)
+	if len(hooks) == 0 {
+		return m.commit(msg)
+	}
+
+	files := make([]string, len(m.Staged))
+	for i, ps := range m.Staged {
+		files[i] = displayPath(m.Info, ps.Path)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancelHooks = cancel
+	m.hooksRunning = true
+	return RunHooksCmd(ctx, hooks, m.Hooks.timeout(), m.pendingID, m.Info.WorkingPath, msg, files)
+}
+
+func (m *Model) showHookResults(msg string, results []HookResult) {
+	var b strings.Builder
+	for _, r := range results {
+		status := styles.AddedStyle.Render("passed")
+		switch {
+		case r.Err != nil:
+			status = styles.LintErrorStyle.Render("error: " + r.Err.Error())
+		case r.ExitCode != 0:
+			status = styles.LintErrorStyle.Render(fmt.Sprintf("failed (exit %d)", r.ExitCode))
+		}
+		fmt.Fprintf(&b, "%s %s\n", styles.InfoHeading.Render(r.Name), status)
+		if output := strings.TrimRight(r.Output, "\n"); output != "" {
+			b.WriteString(output + "\n")
+		}
+		b.WriteString("\n")
+	}
+
+	vp := viewport.New(commitPanelWidth, hooksViewHeight)
+	vp.Style = styles.BaseStyle
+	vp.SetContent(styles.BaseStyle.Width(commitPanelWidth).Render(b.String()))
+	m.hookOutput = vp
+	m.hookResults = results
+	m.hookMessage = msg
+}
+
+func (m *Model) closeHooks() {
+	m.hookResults = nil
+	m.hookMessage = ""
+}
+
+// updateHooks handles keys while the failed hook output blocks the editor.
+func (m *Model) updateHooks(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, m.Keys.Hooks.Force): // [f]orce commit
+		commitMsg := m.hookMessage
+		m.closeHooks()
+		return m.commit(commitMsg)
+	case key.Matches(msg, m.Keys.Hooks.Cancel): // [c]ancel
+		m.closeHooks()
+		m.cancelPending()
+	default:
+		var cmd tea.Cmd
+		m.hookOutput, cmd = m.hookOutput.Update(msg)
+		return cmd
+	}
+	return nil
+}
+
+func (m *Model) hooksView() string {
+	vk := m.hookOutput.KeyMap
+	scroll := key.NewBinding(key.WithHelp(vk.Up.Help().Key+" "+vk.Down.Help().Key, "scroll"))
+	footer := styles.Comment.Render(tui.KeyHints(scroll, m.Keys.Hooks.Force, m.Keys.Hooks.Cancel))
+
+	body := styles.BorderStyle.
+		BorderTop(false).
+		BorderLeft(true).
+		BorderRight(true).
+		BorderBottom(true).
+		Render(lipgloss.JoinVertical(lipgloss.Left, m.hookOutput.View(), footer))
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		styles.GetBorderTopWithTitle("Pre-commit Hooks", commitPanelWidth), body)
+}
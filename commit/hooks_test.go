@@ -0,0 +1,46 @@
+package commit
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DiwashRai/svnty/tui"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestRunHookTimesOut(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	start := time.Now()
+	result := runHook(context.Background(), 100*time.Millisecond, Hook{Command: "sleep 10"}, t.TempDir(), "", "msg", nil)
+	if result.Err == nil || !strings.Contains(result.Err.Error(), "timed out") {
+		t.Errorf("Err = %v, want a timeout", result.Err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("the hung hook was not killed, took %s", elapsed)
+	}
+}
+
+func TestCancelWhileHooksRun(t *testing.T) {
+	m := newTestModel()
+	m.Hooks = HooksConfig{Global: []Hook{{Command: "true"}}}
+	m.Submit()
+	id := m.pendingID
+	if m.Update(tui.PreflightCheckedMsg{ID: id, Message: "Fix the build"}) == nil || !m.hooksRunning {
+		t.Fatal("the hooks did not start")
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.hooksRunning {
+		t.Error("hooksRunning is still set after cancelling")
+	}
+	m.Update(HooksFinishedMsg{ID: id, Message: "Fix the build", Results: []HookResult{{Name: "lint", ExitCode: 1}}})
+	if m.hookResults != nil {
+		t.Error("results of cancelled hooks were shown")
+	}
+}
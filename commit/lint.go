@@ -0,0 +1,169 @@
+package commit
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+
+	"github.com/DiwashRai/svnty/config"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+const (
+	LintFileName = "commit_lint.json"
+
+	RuleSubjectLength   = "subject-length"
+	RuleBlankSecondLine = "blank-second-line"
+	RuleIssueKey        = "issue-key"
+	RuleBannedWords     = "banned-words"
+)
+
+type Severity int
+
+const (
+	SeverityOff Severity = iota
+	SeverityWarning
+	SeverityError
+)
+
+func (s *Severity) UnmarshalJSON(data []byte) error {
+	var str string
+	if err := json.Unmarshal(data, &str); err != nil {
+		return err
+	}
+	switch str {
+	case "off":
+		*s = SeverityOff
+	case "warning":
+		*s = SeverityWarning
+	case "error":
+		*s = SeverityError
+	default:
+		return fmt.Errorf("invalid lint severity %q", str)
+	}
+	return nil
+}
+
+// LintConfig is read from LintFileName in the svnty config dir. Rules that
+// are missing from the file keep their defaults.
+type LintConfig struct {
+	// MaxSubjectLength is counted in terminal columns, so a wide CJK
+	// character takes two.
+	MaxSubjectLength int                 `json:"max_subject_length"`
+	IssuePattern     string              `json:"issue_pattern"`
+	BannedWords      []string            `json:"banned_words"`
+	Severity         map[string]Severity `json:"severity"`
+}
+
+func DefaultLintConfig() LintConfig {
+	return LintConfig{
+		MaxSubjectLength: maxDisplayLength,
+		Severity: map[string]Severity{
+			RuleSubjectLength:   SeverityWarning,
+			RuleBlankSecondLine: SeverityWarning,
+			RuleIssueKey:        SeverityError,
+			RuleBannedWords:     SeverityError,
+		},
+	}
+}
+
+type Violation struct {
+	Rule     string
+	Severity Severity
+	Message  string
+}
+
+type Linter struct {
+	config       LintConfig
+	issueRegex   *regexp.Regexp
+	bannedRegexs []*regexp.Regexp
+}
+
+func NewLinter(config LintConfig, logger *slog.Logger) Linter {
+	l := Linter{config: config}
+
+	if config.IssuePattern != "" {
+		re, err := regexp.Compile(config.IssuePattern)
+		if err != nil {
+			logger.Warn("Invalid issue pattern, disabling rule", "pattern", config.IssuePattern, "error", err)
+		}
+		l.issueRegex = re
+	}
+
+	// \b only knows ASCII word characters, so the boundaries are spelled
+	// out to also hold between letters of other scripts
+	for _, word := range config.BannedWords {
+		re := regexp.MustCompile(`(?i)(?:^|[^\p{L}\p{N}_])` + regexp.QuoteMeta(word) + `(?:$|[^\p{L}\p{N}_])`)
+		l.bannedRegexs = append(l.bannedRegexs, re)
+	}
+	return l
+}
+
+func LoadLinter(logger *slog.Logger) Linter {
+	lc := DefaultLintConfig()
+
+	// severities missing from the file keep their defaults
+	defaults := lc.Severity
+	lc.Severity = nil
+	found, err := config.ReadFile(logger, LintFileName, &lc)
+	if !found || err != nil {
+		return NewLinter(DefaultLintConfig(), logger)
+	}
+	if lc.Severity == nil {
+		lc.Severity = make(map[string]Severity)
+	}
+	for rule, sev := range defaults {
+		if _, ok := lc.Severity[rule]; !ok {
+			lc.Severity[rule] = sev
+		}
+	}
+
+	return NewLinter(lc, logger)
+}
+
+func (l Linter) Lint(msg string) []Violation {
+	msg = strings.TrimSpace(msg)
+	if msg == "" {
+		return nil
+	}
+
+	var violations []Violation
+	add := func(rule, text string) {
+		if sev := l.config.Severity[rule]; sev != SeverityOff {
+			violations = append(violations, Violation{Rule: rule, Severity: sev, Message: text})
+		}
+	}
+
+	lines := strings.Split(msg, "\n")
+	if w := ansi.StringWidth(lines[0]); l.config.MaxSubjectLength > 0 && w > l.config.MaxSubjectLength {
+		add(RuleSubjectLength, fmt.Sprintf("subject is %d columns wide, limit is %d", w, l.config.MaxSubjectLength))
+	}
+
+	if len(lines) > 1 && strings.TrimSpace(lines[1]) != "" {
+		add(RuleBlankSecondLine, "second line should be blank")
+	}
+
+	if l.issueRegex != nil && !l.issueRegex.MatchString(msg) {
+		add(RuleIssueKey, fmt.Sprintf("no issue key matching %s", l.config.IssuePattern))
+	}
+
+	for i, re := range l.bannedRegexs {
+		if re.MatchString(msg) {
+			add(RuleBannedWords, fmt.Sprintf("banned word %q", l.config.BannedWords[i]))
+		}
+	}
+
+	return violations
+}
+
+func HasErrors(violations []Violation) bool {
+	for _, v := range violations {
+		if v.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
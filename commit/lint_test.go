@@ -0,0 +1,65 @@
+package commit
+
+import (
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestBannedWordsUnicodeBoundaries(t *testing.T) {
+	config := DefaultLintConfig()
+	config.BannedWords = []string{"wip", "größe", "テスト"}
+	linter := NewLinter(config, slog.New(slog.DiscardHandler))
+
+	tests := []struct {
+		msg    string
+		banned bool
+	}{
+		{"WIP: fix the build", true},
+		{"Fix wiping of caches", false},
+		{"Größe anpassen", true},
+		{"Fix Größenberechnung", false},
+		{"Schriftgröße anpassen", false},
+		{"テスト", true},
+		{"「テスト」を削除", true},
+		{"éwip", false},
+	}
+	for _, tt := range tests {
+		got := false
+		for _, v := range linter.Lint(tt.msg) {
+			if v.Rule == RuleBannedWords {
+				got = true
+			}
+		}
+		if got != tt.banned {
+			t.Errorf("Lint(%q) banned = %v, want %v", tt.msg, got, tt.banned)
+		}
+	}
+}
+
+func TestSubjectLengthCountsColumns(t *testing.T) {
+	config := DefaultLintConfig()
+	config.MaxSubjectLength = 10
+	linter := NewLinter(config, slog.New(slog.DiscardHandler))
+
+	tests := []struct {
+		msg  string
+		long bool
+	}{
+		{strings.Repeat("a", 10), false},
+		{strings.Repeat("a", 11), true},
+		{strings.Repeat("修", 5), false},
+		{strings.Repeat("修", 6), true}, // 6 runes, 12 columns
+	}
+	for _, tt := range tests {
+		got := false
+		for _, v := range linter.Lint(tt.msg) {
+			if v.Rule == RuleSubjectLength {
+				got = true
+			}
+		}
+		if got != tt.long {
+			t.Errorf("Lint(%q) too long = %v, want %v", tt.msg, got, tt.long)
+		}
+	}
+}
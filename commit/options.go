@@ -0,0 +1,69 @@
+package commit
+
+import (
+	"strings"
+
+	"github.com/DiwashRai/svnty/styles"
+	"github.com/DiwashRai/svnty/svn"
+	"github.com/DiwashRai/svnty/tui"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// newOptionsMenu builds the flags of svn commit over m.Options. Every menu
+// built here edits the same options, so flags set in one show in the others.
+func (m *Model) newOptionsMenu() tui.Menu {
+	k := m.Keys.Options
+	opts := m.Options
+	return tui.Menu{
+		Title: "Commit Options",
+		Flags: []tui.MenuFlag{
+			tui.SwitchFlag(k.KeepChangelists, "--keep-changelists", &opts.KeepChangelists),
+			tui.ValueFlag(k.Depth, "--depth", svn.Depths[1:], &opts.Depth),
+			tui.SwitchFlag(k.IncludeExternals, "--include-externals", &opts.IncludeExternals),
+			tui.SwitchFlag(k.NoUnlock, "--no-unlock", &opts.NoUnlock),
+		},
+		Close: k.Close,
+	}
+}
+
+// CommitMenu is the menu the status panel opens on its commit key, to set
+// the flags of the next commit before starting it.
+func (m *Model) CommitMenu() tui.Menu {
+	k := m.Keys.Options
+	menu := m.newOptionsMenu()
+	menu.Title = "Commit"
+	menu.Prefix = m.Keys.Status.Commit
+	menu.Actions = []tui.Action{
+		{Key: k.Commit, Run: func() tea.Cmd { return tui.CommitMode }},
+		{Key: k.Amend, Run: func() tea.Cmd { return tui.AmendMode }},
+	}
+	return menu
+}
+
+func (m *Model) openOptions() {
+	m.optionsMenu = m.newOptionsMenu()
+	m.showOptions = true
+}
+
+// updateOptions handles keys while the commit options menu is open. Each
+// key toggles a flag, like the switches of a magit transient.
+func (m *Model) updateOptions(msg tea.KeyMsg) tea.Cmd {
+	cmd, open := m.optionsMenu.Update(msg)
+	m.showOptions = open
+	return cmd
+}
+
+func (m *Model) optionsView() string {
+	return m.optionsMenu.View(commitPanelWidth)
+}
+
+// optionsSummary lists the active flags under the editor when the popup is
+// closed.
+func (m *Model) optionsSummary() string {
+	args := m.Options.Args()
+	if len(args) == 0 {
+		return ""
+	}
+	return styles.Comment.Render(" options: " + strings.Join(args, " "))
+}
@@ -0,0 +1,36 @@
+package commit
+
+import (
+	"testing"
+
+	"github.com/DiwashRai/svnty/tui"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestOptionsSharedWithStatusMenu(t *testing.T) {
+	m := newTestModel()
+	statusMenu := m.CommitMenu()
+
+	// --no-unlock from the ctrl+x popup of the editor
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlX})
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("u")})
+	m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+
+	// --keep-changelists from the status panel menu, then commit
+	statusMenu.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("k")})
+	cmd, _ := statusMenu.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	m.Update(cmd())
+
+	if !m.Options.NoUnlock || !m.Options.KeepChangelists {
+		t.Errorf("Options = %+v, want the flags of both menus", *m.Options)
+	}
+	for _, f := range statusMenu.Flags {
+		if f.Name == "--no-unlock" && f.Get() == "" {
+			t.Error("the status menu does not show the flag set in the editor")
+		}
+	}
+	if _, ok := cmd().(tui.CommitModeMsg); !ok {
+		t.Errorf("commit action sent %T", cmd())
+	}
+}
@@ -0,0 +1,113 @@
+package commit
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/DiwashRai/svnty/styles"
+	"github.com/DiwashRai/svnty/svn"
+	"github.com/DiwashRai/svnty/tui"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// PreflightCmd checks the staged paths against the server and commits msg
+// only when nothing would make svn commit fail.
+func PreflightCmd(s svn.Service, id int, msg string) tea.Cmd {
+	return func() tea.Msg {
+		problems, err := s.PreflightCheck()
+		return tui.PreflightCheckedMsg{ID: id, Message: msg, Problems: problems, Err: err}
+	}
+}
+
+func UpdateWorkingCopyCmd(s svn.Service) tea.Cmd {
+	return func() tea.Msg {
+		return tui.PreflightActionDoneMsg{Err: s.UpdateWorkingCopy(svn.UpdateOptions{})}
+	}
+}
+
+// ResolvePathsCmd marks the conflicts among problems resolved, keeping the
+// working files as they are. It refuses while a text conflict still has its
+// markers, which the commit would otherwise send to the repository.
+func ResolvePathsCmd(s svn.Service, problems []svn.PreflightProblem) tea.Cmd {
+	return func() tea.Msg {
+		if paths := svn.UnresolvedPaths(problems); len(paths) > 0 {
+			err := fmt.Errorf("conflict markers are still in %s, edit them out first", strings.Join(paths, ", "))
+			return tui.PreflightActionDoneMsg{Err: err}
+		}
+		return tui.PreflightActionDoneMsg{Err: s.ResolvePaths(svn.ResolvablePaths(problems))}
+	}
+}
+
+// updatePreflight handles keys while the pre-flight summary blocks the
+// editor.
+func (m *Model) updatePreflight(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, m.Keys.Preflight.Update): // [u]pdate working copy
+		m.preflightBusy = true
+		return UpdateWorkingCopyCmd(m.SvnService)
+	case key.Matches(msg, m.Keys.Preflight.Resolve): // [r]esolve conflicts
+		if len(svn.ResolvablePaths(m.problems)) > 0 {
+			m.preflightBusy = true
+			return ResolvePathsCmd(m.SvnService, m.problems)
+		}
+	case key.Matches(msg, m.Keys.Preflight.Cancel): // [c]ancel
+		m.closePreflight()
+	}
+	return nil
+}
+
+// closePreflight also drops the commit, which is submitted again once the
+// problems are fixed.
+func (m *Model) closePreflight() {
+	m.cancelPending()
+	m.problems = nil
+	m.preflightErr = nil
+	m.preflightBusy = false
+}
+
+func (m *Model) preflightView() string {
+	heading := "Staged paths that svn commit would reject:"
+	if len(m.problems) == 0 {
+		heading = "The pre-flight check failed:"
+	}
+	lines := []string{styles.BaseStyle.Render(heading), ""}
+	for _, p := range m.problems {
+		width := commitPanelWidth - 16
+		lines = append(lines, fmt.Sprintf("%s%s",
+			styles.LintErrorStyle.Render(fmt.Sprintf("%-15s", p.Kind)),
+			styles.BaseStyle.Render(tui.Truncate(displayPath(m.Info, p.Path), width, "..."))))
+	}
+	lines = append(lines, "")
+
+	switch {
+	case m.preflightBusy:
+		lines = append(lines, styles.Comment.Render("Working..."))
+	case m.preflightErr != nil:
+		errLines := strings.Split(m.preflightErr.Error(), "\n")
+		for _, l := range errLines {
+			lines = append(lines, styles.LintErrorStyle.Render(tui.Truncate(l, commitPanelWidth-2, "...")))
+		}
+	}
+
+	k := m.Keys.Preflight
+	actions := tui.KeyHints(k.Update, k.Cancel)
+	if len(svn.ResolvablePaths(m.problems)) > 0 {
+		actions = tui.KeyHints(k.Update, k.Resolve, k.Cancel)
+	}
+	lines = append(lines, styles.Comment.Render(actions))
+
+	body := styles.BorderStyle.
+		BorderTop(false).
+		BorderLeft(true).
+		BorderRight(true).
+		BorderBottom(true).
+		Width(commitPanelWidth).
+		PaddingLeft(1).
+		Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		styles.GetBorderTopWithTitle("Cannot Commit", commitPanelWidth), body)
+}
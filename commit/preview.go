@@ -0,0 +1,173 @@
+package commit
+
+import (
+	"fmt"
+	"maps"
+	"path/filepath"
+	"strings"
+
+	"github.com/DiwashRai/svnty/styles"
+	"github.com/DiwashRai/svnty/svn"
+	"github.com/DiwashRai/svnty/tui"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	minPreviewWidth  = 40
+	minPreviewHeight = 5
+	tabWidth         = 4
+)
+
+// preview lists the staged files next to the commit editor, with their line
+// counts and expandable diffs.
+type preview struct {
+	files    []svn.PathStatus
+	diffs    map[string][]string
+	expanded map[string]bool
+	cursor   int
+	yOffset  int
+	// scroll back to the cursor on the next render
+	follow bool
+}
+
+func newPreview() preview {
+	return preview{
+		diffs:    make(map[string][]string),
+		expanded: make(map[string]bool),
+	}
+}
+
+func (p *preview) setFiles(files []svn.PathStatus) {
+	p.files = files
+	p.cursor = min(p.cursor, max(0, len(files)-1))
+}
+
+func (p *preview) addDiffs(diffs map[string][]string) {
+	maps.Copy(p.diffs, diffs)
+}
+
+func (p *preview) paths() []string {
+	paths := make([]string, len(p.files))
+	for i, ps := range p.files {
+		paths[i] = ps.Path
+	}
+	return paths
+}
+
+func (p *preview) moveCursor(delta int) {
+	p.cursor = max(0, min(p.cursor+delta, len(p.files)-1))
+	p.follow = true
+}
+
+func (p *preview) toggleExpanded() {
+	if p.cursor < len(p.files) {
+		path := p.files[p.cursor].Path
+		p.expanded[path] = !p.expanded[path]
+	}
+}
+
+func (p *preview) scroll(delta int) {
+	p.yOffset = max(0, p.yOffset+delta)
+}
+
+// lineCounts returns the number of added and removed lines in a diff. The
+// ---/+++ file headers only come before the first hunk; inside hunks a line
+// like "--- x" is a removed "-- x".
+func lineCounts(lines []string) (added, removed int) {
+	inHunk := false
+	for _, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "@@"):
+			inHunk = true
+		case !inHunk:
+		case strings.HasPrefix(line, "+"):
+			added++
+		case strings.HasPrefix(line, "-"):
+			removed++
+		}
+	}
+	return added, removed
+}
+
+func (p *preview) view(info svn.RepoInfo, width, height int) string {
+	top := styles.GetBorderTopWithTitle("Staged Changes", width-2)
+	height-- // title
+	if height <= 0 {
+		return top
+	}
+
+	if len(p.files) == 0 {
+		return lipgloss.JoinVertical(lipgloss.Left, top, styles.Comment.Render(" Nothing staged"))
+	}
+
+	var (
+		lines     []string
+		cursorIdx int
+	)
+	for i, ps := range p.files {
+		isSel := i == p.cursor
+		runeStyle, textStyle := styles.StatusRune, styles.BaseStyle
+		addedStyle, removedStyle := styles.AddedStyle, styles.RemovedStyle
+		if isSel {
+			cursorIdx = len(lines)
+			runeStyle, textStyle = styles.SelStatusRune, styles.Selected
+			addedStyle, removedStyle = styles.SelAddedStyle, styles.SelRemovedStyle
+		}
+
+		counts := ""
+		diff, fetched := p.diffs[ps.Path]
+		if fetched {
+			added, removed := lineCounts(diff)
+			counts = addedStyle.Render(fmt.Sprintf(" +%d", added)) +
+				removedStyle.Render(fmt.Sprintf(" -%d", removed))
+		}
+
+		path := displayPath(info, ps.Path)
+		// status rune is 3
+		pathWidth := width - 3 - lipgloss.Width(counts)
+		row := runeStyle.Render(" ", string(ps.Status), " ") +
+			textStyle.Render(tui.PadRight(tui.Truncate(path, pathWidth, "..."), pathWidth)) +
+			counts
+		lines = append(lines, row)
+
+		if !p.expanded[ps.Path] {
+			continue
+		}
+		for _, line := range diff {
+			style := styles.BaseStyle
+			switch {
+			case strings.HasPrefix(line, "@@"):
+				style = styles.DiffHeaderStyle
+			case strings.HasPrefix(line, "+"):
+				style = styles.AddedStyle
+			case strings.HasPrefix(line, "-"):
+				style = styles.RemovedStyle
+			}
+			text := strings.ReplaceAll(line, "\t", strings.Repeat(" ", tabWidth))
+			lines = append(lines, style.Render("   "+tui.Truncate(text, width-3, "…")))
+		}
+	}
+
+	if p.follow {
+		if cursorIdx < p.yOffset {
+			p.yOffset = cursorIdx
+		}
+		if cursorIdx >= p.yOffset+height {
+			p.yOffset = cursorIdx - height + 1
+		}
+		p.follow = false
+	}
+	p.yOffset = min(p.yOffset, max(0, len(lines)-height))
+
+	end := min(len(lines), p.yOffset+height)
+	return lipgloss.JoinVertical(lipgloss.Left, append([]string{top}, lines[p.yOffset:end]...)...)
+}
+
+// displayPath shows staged paths relative to the working copy root.
+func displayPath(info svn.RepoInfo, path string) string {
+	if rel, err := filepath.Rel(info.WorkingPath, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return path
+}
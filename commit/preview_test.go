@@ -0,0 +1,41 @@
+package commit
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/DiwashRai/svnty/svn"
+)
+
+func TestPreviewViewTinyHeight(t *testing.T) {
+	p := newPreview()
+	p.setFiles([]svn.PathStatus{{Path: "a.go", Status: 'M'}, {Path: "b.go", Status: 'M'}})
+	p.yOffset = 1
+	for _, height := range []int{-1, 0, 1, 2} {
+		view := p.view(svn.RepoInfo{}, 40, height)
+		if got := strings.Count(view, "\n") + 1; got > max(1, height) {
+			t.Errorf("view(height %d) has %d lines", height, got)
+		}
+	}
+}
+
+func TestLineCountsInsideHunks(t *testing.T) {
+	tests := []struct {
+		name               string
+		diff               []string
+		wantAdded, wantDel int
+	}{
+		{"removed -- x line", []string{"@@ -1,2 +1,1 @@", " keep", "--- x"}, 0, 1},
+		{"added ++ x line", []string{"@@ -1,1 +1,2 @@", " keep", "+++ x"}, 1, 0},
+		{"file headers skipped", []string{"--- a.go\t(revision 1)", "+++ a.go\t(working copy)", "@@ -1 +1 @@", "-old", "+new"}, 1, 1},
+		{"no hunks", []string{""}, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			added, removed := lineCounts(tt.diff)
+			if added != tt.wantAdded || removed != tt.wantDel {
+				t.Errorf("lineCounts = +%d -%d, want +%d -%d", added, removed, tt.wantAdded, tt.wantDel)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package commit
+
+import (
+	"log/slog"
+	"os"
+	"os/user"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/DiwashRai/svnty/config"
+	"github.com/DiwashRai/svnty/svn"
+)
+
+const TemplatesDirName = "templates"
+
+var defaultIssueRegex = regexp.MustCompile(`[A-Z][A-Z0-9]+-\d+`)
+
+// Template pre-fills the commit message. Its body may use the placeholders
+// {{branch}}, {{files}}, {{author}} and {{issue}}.
+type Template struct {
+	Name string
+	Body string
+}
+
+// LoadTemplates reads every file in the templates directory of the svnty
+// config dir, in file name order.
+func LoadTemplates(logger *slog.Logger) []Template {
+	templatesDir, err := config.Path(TemplatesDirName)
+	if err != nil {
+		logger.Warn("Failed to get config directory, disabling templates", "error", err)
+		return nil
+	}
+
+	entries, err := os.ReadDir(templatesDir)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logger.Warn("Failed to read templates directory", "error", err)
+		}
+		return nil
+	}
+
+	var templates []Template
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(templatesDir, entry.Name()))
+		if err != nil {
+			logger.Warn("Failed to read template", "file", entry.Name(), "error", err)
+			continue
+		}
+		templates = append(templates, Template{
+			Name: strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name())),
+			Body: strings.ReplaceAll(string(data), "\r\n", "\n"),
+		})
+	}
+	return templates
+}
+
+func (t Template) Expand(info svn.RepoInfo, staged []svn.PathStatus, linter Linter, author string) string {
+	files := make([]string, len(staged))
+	for i, ps := range staged {
+		files[i] = displayPath(info, ps.Path)
+	}
+
+	branch := info.Branch()
+	r := strings.NewReplacer(
+		"{{branch}}", branch,
+		"{{files}}", strings.Join(files, "\n"),
+		"{{author}}", author,
+		"{{issue}}", linter.IssueKey(branch),
+	)
+	return strings.TrimRight(r.Replace(t.Body), "\n")
+}
+
+// IssueKey returns the first issue key found in s, using the configured issue
+// pattern when it matches and a JIRA style key otherwise.
+func (l Linter) IssueKey(s string) string {
+	if l.issueRegex != nil {
+		if key := l.issueRegex.FindString(s); key != "" {
+			return key
+		}
+	}
+	return defaultIssueRegex.FindString(s)
+}
+
+// author is the svn username of the user: the configured one, else the one
+// svn has cached for the repository, else the login name, which svn+ssh uses.
+func (m *Model) author() string {
+	switch {
+	case m.Author != "":
+		return m.Author
+	case m.username != "":
+		return m.username
+	}
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return os.Getenv("USER")
+}
@@ -0,0 +1,136 @@
+package config
+
+import (
+	"log/slog"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/DiwashRai/svnty/svn"
+)
+
+const (
+	FileName   = "config.json"
+	WCFileName = ".svnty.json" // at the working copy root
+)
+
+// Config holds the defaults read from FileName in the svnty config dir,
+// overridden by WCFileName in the working copy, overridden by CLI flags.
+type Config struct {
+	SvnBinary     string          `json:"svn_binary"`
+	Sections      map[string]bool `json:"sections"` // whether each section starts expanded, by title
+	PageSize      int             `json:"page_size"`
+	ScrollPadding int             `json:"scroll_padding"`
+	Theme         string          `json:"theme"` // "" leaves it to the theme file
+	HistorySize   int             `json:"history_size"`
+	Author        string          `json:"author"` // svn username, "" asks svn for it
+}
+
+func Default() Config {
+	return Config{
+		SvnBinary:     "svn",
+		Sections:      map[string]bool{"unversioned": false},
+		PageSize:      10,
+		ScrollPadding: 2,
+		HistorySize:   svn.DefaultHistorySize,
+	}
+}
+
+// Load reads the global config and then the config of the working copy at
+// wcPath over it. A working copy cannot change the svn binary, as its
+// config may come from the repository.
+func Load(logger *slog.Logger, wcPath string) Config {
+	cfg := Default()
+
+	if path, err := Path(FileName); err != nil {
+		logger.Warn("Failed to get config directory, using default config", "error", err)
+	} else {
+		loadFile(logger, path, &cfg)
+	}
+
+	binary := cfg.SvnBinary
+	loadFile(logger, filepath.Join(wcRoot(wcPath), WCFileName), &cfg)
+	if cfg.SvnBinary != binary {
+		logger.Warn("Ignoring svn_binary set by the working copy config", "svn_binary", cfg.SvnBinary)
+		cfg.SvnBinary = binary
+	}
+
+	cfg.validate(logger)
+	return cfg
+}
+
+// loadFile decodes path over cfg, so only the settings in the file change.
+func loadFile(logger *slog.Logger, path string, cfg *Config) {
+	// decode into a copy so a broken file changes nothing
+	loaded := *cfg
+	loaded.Sections = maps.Clone(cfg.Sections)
+	if found, err := readJSON(logger, path, &loaded); found && err == nil {
+		*cfg = loaded
+	}
+}
+
+// validate puts back the defaults of settings with values that make no
+// sense.
+func (cfg *Config) validate(logger *slog.Logger) {
+	def := Default()
+	if cfg.SvnBinary == "" {
+		cfg.SvnBinary = def.SvnBinary
+	}
+	if cfg.PageSize < 1 {
+		logger.Warn("Invalid page_size, using default", "page_size", cfg.PageSize)
+		cfg.PageSize = def.PageSize
+	}
+	if cfg.ScrollPadding < 0 {
+		logger.Warn("Invalid scroll_padding, using default", "scroll_padding", cfg.ScrollPadding)
+		cfg.ScrollPadding = def.ScrollPadding
+	}
+	if cfg.HistorySize < 1 {
+		logger.Warn("Invalid history_size, using default", "history_size", cfg.HistorySize)
+		cfg.HistorySize = def.HistorySize
+	}
+	for name := range cfg.Sections {
+		if _, ok := sectionIdx(name); !ok {
+			logger.Warn("Unknown section in config", "section", name)
+		}
+	}
+}
+
+func sectionIdx(name string) (svn.SectionIdx, bool) {
+	for i, title := range svn.SectionTitles {
+		if strings.EqualFold(title, name) {
+			return svn.SectionIdx(i), true
+		}
+	}
+	return 0, false
+}
+
+// Collapsed reports which sections start collapsed.
+func (cfg Config) Collapsed() [svn.NumSections]bool {
+	var collapsed [svn.NumSections]bool
+	for name, expanded := range cfg.Sections {
+		if i, ok := sectionIdx(name); ok {
+			collapsed[i] = !expanded
+		}
+	}
+	return collapsed
+}
+
+// wcRoot walks up from path to the directory holding the .svn admin
+// directory, or returns path when there is none.
+func wcRoot(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+	for dir := abs; ; {
+		if info, err := os.Stat(filepath.Join(dir, ".svn")); err == nil && info.IsDir() {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return abs
+		}
+		dir = parent
+	}
+}
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/DiwashRai/svnty/svn"
+)
+
+// Path returns the path of name in the svnty config dir.
+func Path(name string) (string, error) {
+	svntyDir, err := svn.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(svntyDir, name), nil
+}
+
+// ReadFile decodes the JSON file name in the svnty config dir into v. A
+// missing file is not an error: found is false and v is left alone. Other
+// failures are logged and returned, and v may then be partly decoded, so
+// callers fall back to their defaults.
+//
+// This package must not import the packages that load their settings with
+// it.
+func ReadFile(logger *slog.Logger, name string, v any) (found bool, err error) {
+	path, err := Path(name)
+	if err != nil {
+		logger.Warn("Failed to get config directory", "file", name, "error", err)
+		return false, err
+	}
+	return readJSON(logger, path, v)
+}
+
+func readJSON(logger *slog.Logger, path string, v any) (bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		logger.Warn("Failed to read config file", "path", path, "error", err)
+		return false, err
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		logger.Warn("Failed to parse config file", "path", path, "error", err)
+		return true, err
+	}
+	return true, nil
+}
@@ -0,0 +1,36 @@
+package config
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadFile(t *testing.T) {
+	logger := slog.New(slog.DiscardHandler)
+	dir := t.TempDir()
+	write := func(name, data string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	cfg := Default()
+	loadFile(logger, filepath.Join(dir, "missing.json"), &cfg)
+	if cfg.PageSize != Default().PageSize {
+		t.Errorf("a missing file changed page_size to %d", cfg.PageSize)
+	}
+
+	loadFile(logger, write("broken.json", `{"page_size": 20, "sections": {"staged": "no"}}`), &cfg)
+	if cfg.PageSize != Default().PageSize || len(cfg.Sections) != 1 {
+		t.Errorf("a broken file changed the config: %+v", cfg)
+	}
+
+	loadFile(logger, write("good.json", `{"page_size": 20, "sections": {"staged": false}}`), &cfg)
+	if cfg.PageSize != 20 || cfg.Sections["staged"] || cfg.Sections["unversioned"] {
+		t.Errorf("the file was not decoded over the defaults: %+v", cfg)
+	}
+}
@@ -10,8 +10,14 @@ import (
 	"github.com/charmbracelet/lipgloss"
 )
 
+const (
+	labelWidth = 14 // "Working path: "
+)
+
 type Model struct {
 	SvnService svn.Service
+	Info       svn.RepoInfo
+	Width      int
 }
 
 func New(svc svn.Service) Model {
@@ -23,13 +29,29 @@ func (m *Model) Init() tea.Cmd {
 }
 
 func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.Width = msg.Width
+	case tui.InfoFetchedMsg:
+		m.Info = msg.Info
+	case tui.CommitSuccessMsg:
+		// svn info keeps reporting the base revision of the working copy
+		if msg.Revision != 0 {
+			m.Info.Revision = msg.Revision
+		}
+	}
 	return m, nil
 }
 
 func (m *Model) View() string {
-	info := m.SvnService.CurrentInfo()
+	info := m.Info
 	wp, url, rev := info.WorkingPath, info.RemoteURL, strconv.FormatUint(uint64(info.Revision), 10)
 
+	// padding on left is 1
+	availableWidth := m.Width - (styles.GutterLen + 1) - labelWidth
+	wp = tui.Truncate(wp, availableWidth, "...")
+	url = tui.Truncate(url, availableWidth, "...")
+
 	return tui.JoinVerticalStyled(
 		lipgloss.Left,
 		styles.BaseStyle,
@@ -4,8 +4,10 @@ import (
 	"flag"
 	"fmt"
 	"github.com/DiwashRai/svnty/app"
+	"github.com/DiwashRai/svnty/config"
 	"github.com/DiwashRai/svnty/logging"
 	"github.com/DiwashRai/svnty/svn"
+	"github.com/DiwashRai/svnty/watcher"
 	"os"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -16,6 +18,13 @@ func main() {
 	workingCopyPath := flag.String("path", ".", "path of svn repo")
 	useMock := flag.Bool("mock", false, "use mocked SVN data")
 	logPath := flag.String("log", "", "write logs to this file")
+	defaults := config.Default()
+	svnBinary := flag.String("svn", defaults.SvnBinary, "svn executable to run")
+	theme := flag.String("theme", "", "color theme: auto, dark or light")
+	pageSize := flag.Int("page-size", defaults.PageSize, "rows moved by page up/down")
+	scrollPadding := flag.Int("scroll-padding", defaults.ScrollPadding, "rows kept visible around the cursor")
+	historySize := flag.Int("history-size", defaults.HistorySize, "number of commit messages kept per repository")
+	author := flag.String("author", "", "svn username to find your commits by, asked from svn when empty")
 	flag.Parse()
 
 	rootLogger, closeLogFile, err := logging.New(*logPath)
@@ -25,6 +34,25 @@ func main() {
 	}
 	defer closeLogFile()
 
+	// flags given on the command line override both config files
+	cfg := config.Load(rootLogger, *workingCopyPath)
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "svn":
+			cfg.SvnBinary = *svnBinary
+		case "theme":
+			cfg.Theme = *theme
+		case "page-size":
+			cfg.PageSize = max(1, *pageSize)
+		case "scroll-padding":
+			cfg.ScrollPadding = max(0, *scrollPadding)
+		case "history-size":
+			cfg.HistorySize = *historySize
+		case "author":
+			cfg.Author = *author
+		}
+	})
+
 	var svc svn.Service
 	if *useMock {
 		var mockSvc svn.MockService
@@ -32,12 +60,30 @@ func main() {
 	} else {
 		realSvc := svn.RealService{
 			WorkingCopyPath: *workingCopyPath,
+			Binary:          cfg.SvnBinary,
 			Logger:          rootLogger,
 		}
 		svc = &realSvc
 	}
 
-	model := app.New(svc, rootLogger)
+	model := app.New(svc, rootLogger, app.Options{
+		WorkingCopyPath: *workingCopyPath,
+		Config:          cfg,
+	})
+
+	if !*useMock {
+		ignoreRules, err := svc.FetchIgnoreRules()
+		if err != nil {
+			rootLogger.Warn("Failed to fetch svn:ignore rules", "error", err)
+		}
+		w, err := watcher.New(*workingCopyPath, ignoreRules.Match, rootLogger)
+		if err != nil {
+			rootLogger.Warn("Failed to start file watcher, auto refresh disabled", "error", err)
+		} else {
+			defer w.Close()
+			model.Watcher = w
+		}
+	}
 
 	p := tea.NewProgram(&model, tea.WithAltScreen())
 	if _, err := p.Run(); err != nil {
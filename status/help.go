@@ -0,0 +1,56 @@
+package status
+
+import (
+	"slices"
+
+	"github.com/DiwashRai/svnty/svn"
+	"github.com/DiwashRai/svnty/tui"
+
+	"github.com/charmbracelet/bubbles/key"
+)
+
+// Help lists the bindings that apply to the element under the cursor.
+func (m *Model) Help() tui.ContextHelp {
+	if m.menu != nil {
+		return m.menu.Help()
+	}
+
+	k := m.Keys.Status
+
+	var title string
+	var here []key.Binding
+	switch m.Cursor.ElemType {
+	case HeaderElem:
+		title = "section header"
+		here = []key.Binding{k.Toggle}
+	case PathElem:
+		title = "path"
+		if m.Cursor.Section == svn.SectionStaged {
+			here = []key.Binding{k.Unstage, k.Diff}
+		} else {
+			here = []key.Binding{k.Stage, k.Diff}
+		}
+	case DiffElem:
+		title = "diff line"
+		here = []key.Binding{k.Diff, k.SideBySide, k.Wrap}
+		if !m.WrapLines {
+			here = append(here, k.ScrollLeft, k.ScrollRight)
+		}
+	}
+
+	general := []key.Binding{k.Commit, k.Update, k.Amend, k.Refresh}
+	if m.bannerRev != 0 {
+		general = append(general, k.CopyRevision, k.CopyURL)
+	}
+	general = append(general, k.Quit)
+
+	return tui.ContextHelp{
+		Title: title,
+		Short: slices.Concat(here, []key.Binding{k.Commit, k.Help, k.Quit}),
+		Full: [][]key.Binding{
+			{k.Up, k.Down, k.PageUp, k.PageDown},
+			here,
+			general,
+		},
+	}
+}
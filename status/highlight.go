@@ -0,0 +1,179 @@
+package status
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/DiwashRai/svnty/styles"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/lexers"
+)
+
+// tokenColor maps chroma token types onto the syntax colors of the theme. An empty
+// string leaves the token in the line's own foreground color.
+func tokenColor(t chroma.TokenType) string {
+	switch {
+	case t.InSubCategory(chroma.CommentPreproc):
+		return styles.PreprocColor
+	case t.InCategory(chroma.Comment):
+		return styles.CommentColor
+	case t.InSubCategory(chroma.LiteralString):
+		return styles.StringColor
+	case t.InSubCategory(chroma.LiteralNumber):
+		return styles.NumColor
+	case t == chroma.KeywordType, t == chroma.NameClass:
+		return styles.TypeColor
+	case t.InCategory(chroma.Keyword), t == chroma.NameTag:
+		return styles.KeywordColor
+	case t.InSubCategory(chroma.NameFunction), t == chroma.NameDecorator:
+		return styles.FunctionColor
+	case t.InSubCategory(chroma.NameBuiltin), t == chroma.NameAttribute:
+		return styles.BuiltinColor
+	case t == chroma.NameConstant:
+		return styles.SpecialColor
+	case t.InCategory(chroma.Operator):
+		return styles.OperatorColor
+	}
+	return ""
+}
+
+// highlightDiff lexes the old and new side of each hunk separately, so that
+// multi-line strings and comments are colored correctly, and returns the
+// syntax spans of every diff line without its +/- prefix. Returns nil when no
+// lexer matches the file extension.
+func highlightDiff(path string, lines []string) [][]span {
+	lexer := lexers.Match(filepath.Base(path))
+	if lexer == nil {
+		return nil
+	}
+	lexer = chroma.Coalesce(lexer)
+
+	var (
+		syntax         = make([][]span, len(lines))
+		oldIdx, newIdx []int
+	)
+	flush := func() {
+		highlightLines(lexer, lines, oldIdx, syntax)
+		highlightLines(lexer, lines, newIdx, syntax)
+		oldIdx, newIdx = oldIdx[:0], newIdx[:0]
+	}
+
+	for i, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "-"):
+			oldIdx = append(oldIdx, i)
+		case strings.HasPrefix(line, "+"):
+			newIdx = append(newIdx, i)
+		case strings.HasPrefix(line, " "):
+			oldIdx = append(oldIdx, i)
+			newIdx = append(newIdx, i)
+		default: // hunk headers and notes end a run of code
+			flush()
+		}
+	}
+	flush()
+
+	return syntax
+}
+
+func highlightLines(lexer chroma.Lexer, lines []string, idxs []int, syntax [][]span) {
+	if len(idxs) == 0 {
+		return
+	}
+
+	var b strings.Builder
+	for k, i := range idxs {
+		if k > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString(lines[i][1:])
+	}
+
+	it, err := lexer.Tokenise(nil, b.String())
+	if err != nil {
+		return
+	}
+
+	// context lines are lexed on both sides, keep only the last result
+	for _, i := range idxs {
+		syntax[i] = nil
+	}
+
+	k := 0
+	for token := it(); token != chroma.EOF; token = it() {
+		for p, part := range strings.Split(token.Value, "\n") {
+			if p > 0 {
+				k++
+			}
+			if k >= len(idxs) {
+				return
+			}
+			if part != "" {
+				syntax[idxs[k]] = append(syntax[idxs[k]], span{Text: part, Color: tokenColor(token.Type)})
+			}
+		}
+	}
+}
+
+// unifiedSpans returns the spans of a unified diff line, starting with its
+// +/- prefix, or nil when the line has neither a word diff nor syntax colors.
+func unifiedSpans(line string, words, syntax []span) []span {
+	if syntax != nil {
+		words = overlaySyntax(orPlain(words, line[1:]), syntax)
+	}
+	if words == nil {
+		return nil
+	}
+
+	prefix := span{Text: line[:1]}
+	switch line[0] {
+	case '+':
+		prefix.Color = styles.AddedColor
+	case '-':
+		prefix.Color = styles.RemovedColor
+	}
+	return append([]span{prefix}, words...)
+}
+
+// overlaySyntax splits spans at the syntax token boundaries so each piece
+// carries both its word diff state and its syntax color. Both must cover the
+// same text, otherwise spans are returned unchanged.
+func overlaySyntax(spans, syntax []span) []span {
+	if len(spans) == 0 || len(syntax) == 0 || spansText(spans) != spansText(syntax) {
+		return spans
+	}
+
+	var (
+		out  []span
+		i, j int
+		a, b = spans[0].Text, syntax[0].Text
+	)
+	for {
+		n := min(len(a), len(b))
+		out = append(out, span{Text: a[:n], Changed: spans[i].Changed, Color: syntax[j].Color})
+		a, b = a[n:], b[n:]
+
+		if a == "" {
+			if i++; i == len(spans) {
+				break
+			}
+			a = spans[i].Text
+		}
+		if b == "" {
+			if j++; j == len(syntax) {
+				break
+			}
+			b = syntax[j].Text
+		}
+	}
+	return out
+}
+
+func spansText(spans []span) string {
+	var b strings.Builder
+	for _, s := range spans {
+		b.WriteString(s.Text)
+	}
+	return b.String()
+}
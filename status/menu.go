@@ -0,0 +1,84 @@
+package status
+
+import (
+	"fmt"
+
+	"github.com/DiwashRai/svnty/svn"
+	"github.com/DiwashRai/svnty/tui"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// actions lists what the keys of the status panel do, checked after the
+// prefix keys of the menus. A command with flags gets a menu instead.
+func (m *Model) actions() []tui.Action {
+	k := m.Keys.Status
+	prefetch := func(move func() bool) func() tea.Cmd {
+		return func() tea.Cmd {
+			move()
+			return m.PrefetchVisibleDiffs()
+		}
+	}
+	toggle := func(flip func()) func() tea.Cmd {
+		return func() tea.Cmd {
+			flip()
+			return nil
+		}
+	}
+
+	return []tui.Action{
+		{Key: k.Amend, Run: func() tea.Cmd { return tui.AmendMode }},
+		{Key: k.CopyRevision, Run: func() tea.Cmd {
+			if m.bannerRev == 0 {
+				return nil
+			}
+			return tui.CopyToClipboard(fmt.Sprint(m.bannerRev))
+		}},
+		{Key: k.CopyURL, Run: func() tea.Cmd {
+			if m.bannerRev == 0 {
+				return nil
+			}
+			return tui.CopyToClipboard(fmt.Sprintf("%s@%d", m.Info.RemoteURL, m.bannerRev))
+		}},
+		{Key: k.Up, Run: prefetch(m.Up)},
+		{Key: k.Down, Run: prefetch(m.Down)},
+		{Key: k.PageUp, Run: prefetch(m.PageUp)},
+		{Key: k.PageDown, Run: prefetch(m.PageDown)},
+		{Key: k.Quit, Run: func() tea.Cmd { return tui.Quit }},
+		{Key: k.Refresh, Run: func() tea.Cmd { return tui.FetchStatus }},
+		{Key: k.Stage, Run: m.Stage},
+		{Key: k.Unstage, Run: m.Unstage},
+		{Key: k.Diff, Run: m.Diff},
+		{Key: k.SideBySide, Run: toggle(m.ToggleSideBySide)},
+		{Key: k.Wrap, Run: toggle(m.ToggleWrap)},
+		{Key: k.ScrollLeft, Run: toggle(m.ScrollLeft)},
+		{Key: k.ScrollRight, Run: toggle(m.ScrollRight)},
+		{Key: k.Toggle, Run: m.ToggleSectionExpand},
+	}
+}
+
+// updateMenu opens on the update key, to set the flags of svn update before
+// running it.
+func (m *Model) updateMenu() tui.Menu {
+	k := m.Keys.Update
+	opts := m.updateOpts
+	return tui.Menu{
+		Title:  "Update",
+		Prefix: m.Keys.Status.Update,
+		Flags: []tui.MenuFlag{
+			tui.SwitchFlag(k.IgnoreExternals, "--ignore-externals", &opts.IgnoreExternals),
+			tui.ValueFlag(k.Depth, "--depth", svn.Depths[1:], &opts.Depth),
+			tui.ValueFlag(k.Accept, "--accept", svn.Accepts[1:], &opts.Accept),
+		},
+		Actions: []tui.Action{
+			{Key: k.Update, Run: func() tea.Cmd { return UpdateCmd(m.SvnService, *opts) }},
+		},
+		Close: k.Close,
+	}
+}
+
+func UpdateCmd(s svn.Service, opts svn.UpdateOptions) tea.Cmd {
+	return func() tea.Msg {
+		return tui.UpdatedMsg{Err: s.UpdateWorkingCopy(opts)}
+	}
+}
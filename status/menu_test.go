@@ -0,0 +1,61 @@
+package status
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/DiwashRai/svnty/svn"
+	"github.com/DiwashRai/svnty/tui"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type updateService struct {
+	svn.MockService
+	opts *svn.UpdateOptions
+}
+
+func (s *updateService) UpdateWorkingCopy(opts svn.UpdateOptions) error {
+	s.opts = &opts
+	return nil
+}
+
+func keyMsg(k string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+}
+
+func TestUpdateMenuPassesFlags(t *testing.T) {
+	svc := &updateService{}
+	m := &Model{SvnService: svc, Logger: slog.New(slog.DiscardHandler), Keys: tui.DefaultKeyMap()}
+	m.Init()
+
+	var cmd tea.Cmd
+	for _, k := range []string{"U", "x", "d", "d", "u"} {
+		cmd = m.Update(keyMsg(k))
+	}
+	if cmd == nil {
+		t.Fatal("update key returned no command")
+	}
+	if msg, ok := cmd().(tui.UpdatedMsg); !ok || msg.Err != nil {
+		t.Fatalf("got %#v, want a successful UpdatedMsg", msg)
+	}
+	want := svn.UpdateOptions{IgnoreExternals: true, Depth: "files"}
+	if svc.opts == nil || *svc.opts != want {
+		t.Errorf("UpdateWorkingCopy got %+v, want %+v", svc.opts, want)
+	}
+	if m.menu != nil {
+		t.Error("menu still open after updating")
+	}
+}
+
+func TestActionsRunWithoutMenu(t *testing.T) {
+	m := &Model{SvnService: &svn.MockService{}, Logger: slog.New(slog.DiscardHandler), Keys: tui.DefaultKeyMap()}
+	m.Init()
+
+	if cmd := m.Update(keyMsg("g")); cmd == nil {
+		t.Error("refresh key returned no command")
+	}
+	if m.menu != nil {
+		t.Error("refresh key opened a menu")
+	}
+}
@@ -0,0 +1,197 @@
+package status
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/DiwashRai/svnty/styles"
+	"github.com/DiwashRai/svnty/tui"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+type rowKind int
+
+const (
+	hunkHeaderRow rowKind = iota
+	contextRow
+	changedRow
+	noteRow // "\ No newline at end of file", property changes etc.
+)
+
+const (
+	lineNumWidth = 5 // 4 digits + 1 space
+	tabWidth     = 4
+)
+
+var hunkHeaderRegex = regexp.MustCompile(`^@@ -(\d+)(?:,\d+)? \+(\d+)(?:,\d+)? @@`)
+
+// diffRow is one line of a side by side diff. OldIdx and NewIdx point back
+// into the unified diff lines, and are -1 when that side is empty. Changed
+// rows with both sides set also carry their word level diff.
+type diffRow struct {
+	Kind               rowKind
+	Old, New           string
+	OldNum, NewNum     int
+	OldIdx, NewIdx     int
+	OldSpans, NewSpans []span
+}
+
+// highlight overlays the syntax spans of the lines this row was built from.
+func (r *diffRow) highlight(syntax [][]span) {
+	if syntax == nil || (r.Kind != contextRow && r.Kind != changedRow) {
+		return
+	}
+	if r.OldIdx >= 0 {
+		r.OldSpans = overlaySyntax(orPlain(r.OldSpans, r.Old), syntax[r.OldIdx])
+	}
+	if r.NewIdx >= 0 {
+		r.NewSpans = overlaySyntax(orPlain(r.NewSpans, r.New), syntax[r.NewIdx])
+	}
+}
+
+// unifiedIdx returns the unified diff line this row was built from.
+func (r diffRow) unifiedIdx() int {
+	if r.OldIdx >= 0 {
+		return r.OldIdx
+	}
+	return r.NewIdx
+}
+
+// pairDiffLines turns unified diff lines into side by side rows. Within each
+// hunk, runs of removed lines are paired up with the added lines after them.
+func pairDiffLines(lines []string) []diffRow {
+	var (
+		rows             []diffRow
+		removed, added   []int
+		oldNum, newNum   int
+		oldNums, newNums []int
+	)
+
+	flush := func() {
+		for i := range max(len(removed), len(added)) {
+			row := diffRow{Kind: changedRow, OldIdx: -1, NewIdx: -1}
+			if i < len(removed) {
+				row.Old, row.OldNum, row.OldIdx = lines[removed[i]][1:], oldNums[i], removed[i]
+			}
+			if i < len(added) {
+				row.New, row.NewNum, row.NewIdx = lines[added[i]][1:], newNums[i], added[i]
+			}
+			if row.OldIdx >= 0 && row.NewIdx >= 0 {
+				row.OldSpans, row.NewSpans = wordDiff(row.Old, row.New)
+			}
+			rows = append(rows, row)
+		}
+		removed, added = removed[:0], added[:0]
+		oldNums, newNums = oldNums[:0], newNums[:0]
+	}
+
+	for i, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "@@"):
+			flush()
+			if m := hunkHeaderRegex.FindStringSubmatch(line); m != nil {
+				oldNum, _ = strconv.Atoi(m[1])
+				newNum, _ = strconv.Atoi(m[2])
+			}
+			rows = append(rows, diffRow{Kind: hunkHeaderRow, Old: line, OldIdx: i, NewIdx: -1})
+		case strings.HasPrefix(line, "-"):
+			// a removal after additions starts a new change block
+			if len(added) > 0 {
+				flush()
+			}
+			removed = append(removed, i)
+			oldNums = append(oldNums, oldNum)
+			oldNum++
+		case strings.HasPrefix(line, "+"):
+			added = append(added, i)
+			newNums = append(newNums, newNum)
+			newNum++
+		case strings.HasPrefix(line, " "):
+			flush()
+			rows = append(rows, diffRow{
+				Kind: contextRow,
+				Old:  line[1:], New: line[1:],
+				OldNum: oldNum, NewNum: newNum,
+				OldIdx: i, NewIdx: i,
+			})
+			oldNum++
+			newNum++
+		default:
+			flush()
+			rows = append(rows, diffRow{Kind: noteRow, Old: line, OldIdx: i, NewIdx: -1})
+		}
+	}
+	flush()
+
+	return rows
+}
+
+// sideBySideRow maps a unified diff line index to the row that shows it.
+func sideBySideRow(rows []diffRow, unifiedIdx int) int {
+	for i, row := range rows {
+		if row.OldIdx == unifiedIdx || row.NewIdx == unifiedIdx {
+			return i
+		}
+	}
+	return 0
+}
+
+// wordSpans maps the unified diff line indexes of paired changed lines to
+// their word level diffs.
+func wordSpans(rows []diffRow) map[int][]span {
+	spans := make(map[int][]span)
+	for _, row := range rows {
+		if row.OldSpans != nil {
+			spans[row.OldIdx] = row.OldSpans
+			spans[row.NewIdx] = row.NewSpans
+		}
+	}
+	return spans
+}
+
+func renderCell(num int, spans []span, xOffset, width int, numStyle, textStyle, emphStyle lipgloss.Style) string {
+	numStr := strings.Repeat(" ", lineNumWidth)
+	if num > 0 {
+		numStr = fmt.Sprintf("%*d ", lineNumWidth-1, num)
+	}
+
+	textWidth := max(0, width-lineNumWidth)
+	text := renderSpans(dropSpans(expandTabs(spans), xOffset), textWidth, "…", textStyle, emphStyle)
+	pad := tui.PadRight("", textWidth-ansi.StringWidth(text))
+	return numStyle.Render(numStr) + text + textStyle.Render(pad)
+}
+
+type sideBySideStyles struct {
+	text, added, removed, header, lineNum lipgloss.Style
+	addedWord, removedWord                lipgloss.Style
+}
+
+func (m *Model) renderSideBySide(row diffRow, st sideBySideStyles) string {
+	// padding on left is 1
+	availableWidth := m.Width - (styles.GutterLen + 1)
+
+	switch row.Kind {
+	case hunkHeaderRow:
+		return st.header.Render(tui.Truncate(row.Old, availableWidth, "…"))
+	case noteRow:
+		return st.text.Render(tui.Truncate(row.Old, availableWidth, "…"))
+	}
+
+	colWidth := (availableWidth - 1) / 2
+	oldStyle, newStyle := st.text, st.text
+	if row.Kind == changedRow {
+		oldStyle, newStyle = st.removed, st.added
+	}
+
+	return tui.JoinHorizontalStyled(
+		lipgloss.Top,
+		st.text,
+		renderCell(row.OldNum, orPlain(row.OldSpans, row.Old), m.XOffset, colWidth, st.lineNum, oldStyle, st.removedWord),
+		st.lineNum.Render("│"),
+		renderCell(row.NewNum, orPlain(row.NewSpans, row.New), m.XOffset, colWidth, st.lineNum, newStyle, st.addedWord),
+	)
+}
@@ -0,0 +1,141 @@
+package status
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// span is a piece of a diff line. Changed marks the words that differ from
+// the line it was paired with, Color is an optional syntax foreground color.
+type span struct {
+	Text    string
+	Changed bool
+	Color   string
+}
+
+func orPlain(spans []span, text string) []span {
+	if spans == nil {
+		return []span{{Text: text}}
+	}
+	return spans
+}
+
+func expandTabs(spans []span) []span {
+	expanded := make([]span, len(spans))
+	for i, s := range spans {
+		expanded[i] = s
+		expanded[i].Text = strings.ReplaceAll(s.Text, "\t", strings.Repeat(" ", tabWidth))
+	}
+	return expanded
+}
+
+// dropSpans removes the first n display cells from spans.
+func dropSpans(spans []span, n int) []span {
+	var out []span
+	for _, s := range spans {
+		w := ansi.StringWidth(s.Text)
+		if n >= w {
+			n -= w
+			continue
+		}
+		if n > 0 {
+			// a wide rune cut in half is dropped and its remaining cell
+			// padded, so the rest of the line keeps its columns
+			s.Text = ansi.TruncateLeft(s.Text, n, "")
+			if cut := ansi.StringWidth(s.Text) - (w - n); cut > 0 {
+				_, size := utf8.DecodeRuneInString(s.Text)
+				s.Text = strings.Repeat(" ", cut) + s.Text[size:]
+			}
+			n = 0
+		}
+		out = append(out, s)
+	}
+	return out
+}
+
+// wrapSpans splits spans into lines of at most width display cells. A width of
+// 0 or less means no limit.
+func wrapSpans(spans []span, width int) [][]span {
+	if width <= 0 {
+		return [][]span{spans}
+	}
+
+	var (
+		lines [][]span
+		line  []span
+		used  int
+	)
+	for _, s := range spans {
+		text := s.Text
+		for text != "" {
+			if used >= width {
+				lines = append(lines, line)
+				line, used = nil, 0
+			}
+
+			head := ansi.Truncate(text, width-used, "")
+			if head == "" {
+				// wide character that doesn't fit in what is left of the line
+				if used > 0 {
+					used = width
+					continue
+				}
+				_, size := utf8.DecodeRuneInString(text)
+				head = text[:size]
+			}
+
+			piece := s
+			piece.Text = head
+			line = append(line, piece)
+			used += ansi.StringWidth(head)
+			text = text[len(head):]
+		}
+	}
+	return append(lines, line)
+}
+
+// renderSpans styles spans and cuts them off at width display cells, ending
+// with tail when cut. A width of 0 or less means no limit.
+func renderSpans(spans []span, width int, tail string, base, emph lipgloss.Style) string {
+	var (
+		b     strings.Builder
+		used  int
+		limit = width - ansi.StringWidth(tail)
+	)
+
+	total := 0
+	for _, s := range spans {
+		total += ansi.StringWidth(s.Text)
+	}
+	truncate := width > 0 && total > width
+
+	for _, s := range spans {
+		style := base
+		if s.Changed {
+			style = emph
+		}
+		if s.Color != "" {
+			style = style.Foreground(lipgloss.Color(s.Color))
+		}
+
+		text := s.Text
+		if truncate {
+			text = ansi.Truncate(text, max(0, limit-used), "")
+		}
+		used += ansi.StringWidth(text)
+		if text != "" {
+			b.WriteString(style.Render(text))
+		}
+
+		if truncate && used >= limit {
+			break
+		}
+	}
+	if truncate {
+		b.WriteString(base.Render(tail))
+	}
+	return b.String()
+}
@@ -0,0 +1,122 @@
+package status
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+func spanTexts(spans []span) []string {
+	var texts []string
+	for _, s := range spans {
+		texts = append(texts, s.Text)
+	}
+	return texts
+}
+
+func TestWrapSpans(t *testing.T) {
+	tests := []struct {
+		name  string
+		spans []span
+		width int
+		want  [][]string
+	}{
+		{
+			name:  "cjk splits on cell boundary",
+			spans: []span{{Text: "日本語です"}},
+			width: 4,
+			want:  [][]string{{"日本"}, {"語で"}, {"す"}},
+		},
+		{
+			name:  "wide rune at boundary moves to next line",
+			spans: []span{{Text: "a日本"}},
+			width: 2,
+			want:  [][]string{{"a"}, {"日"}, {"本"}},
+		},
+		{
+			name:  "spans keep their own pieces",
+			spans: []span{{Text: "ab"}, {Text: "日本", Changed: true}},
+			width: 3,
+			want:  [][]string{{"ab"}, {"日"}, {"本"}},
+		},
+		{
+			name:  "wide rune wider than width is kept whole",
+			spans: []span{{Text: "日本"}},
+			width: 1,
+			want:  [][]string{{"日"}, {"本"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got [][]string
+			for _, line := range wrapSpans(tt.spans, tt.width) {
+				got = append(got, spanTexts(line))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("wrapSpans() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWrapSpansKeepsChanged(t *testing.T) {
+	lines := wrapSpans([]span{{Text: "ab"}, {Text: "日本", Changed: true}}, 3)
+	for _, line := range lines[1:] {
+		for _, s := range line {
+			if !s.Changed {
+				t.Errorf("piece %q of a changed span lost Changed", s.Text)
+			}
+		}
+	}
+}
+
+func TestDropSpans(t *testing.T) {
+	tests := []struct {
+		name  string
+		spans []span
+		n     int
+		want  []string
+	}{
+		{"whole span dropped", []span{{Text: "日本"}, {Text: "語"}}, 4, []string{"語"}},
+		{"cjk dropped by cells", []span{{Text: "日本語"}}, 2, []string{"本語"}},
+		{"ascii then cjk", []span{{Text: "ab"}, {Text: "日本"}}, 4, []string{"本"}},
+		{"half a wide rune becomes a space", []span{{Text: "日本"}}, 1, []string{" 本"}},
+		{"nothing dropped", []span{{Text: "日本"}}, 0, []string{"日本"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := spanTexts(dropSpans(tt.spans, tt.n)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("dropSpans(%d) = %q, want %q", tt.n, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderSpans(t *testing.T) {
+	plain := lipgloss.NewStyle()
+	tests := []struct {
+		name  string
+		spans []span
+		width int
+		tail  string
+		want  string
+	}{
+		{"cjk fits", []span{{Text: "日本"}, {Text: "語"}}, 6, "...", "日本語"},
+		{"cjk cut with tail", []span{{Text: "日本語です"}}, 7, "...", "日本..."},
+		{"wide rune not split at limit", []span{{Text: "a日本語"}}, 6, "..", "a日.."},
+		{"no limit", []span{{Text: "日本語です"}}, 0, "...", "日本語です"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ansi.Strip(renderSpans(tt.spans, tt.width, tt.tail, plain, plain))
+			if got != tt.want {
+				t.Errorf("renderSpans() = %q, want %q", got, tt.want)
+			}
+			if tt.width > 0 && ansi.StringWidth(got) > tt.width {
+				t.Errorf("renderSpans() is %d cells wide, limit %d", ansi.StringWidth(got), tt.width)
+			}
+		})
+	}
+}
@@ -1,17 +1,23 @@
 package status
 
 import (
+	"fmt"
 	"log/slog"
+	"maps"
 	"reflect"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/DiwashRai/svnty/styles"
 	"github.com/DiwashRai/svnty/svn"
 	"github.com/DiwashRai/svnty/tui"
 
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 )
 
 type ElementType int
@@ -24,7 +30,8 @@ const (
 )
 
 const (
-	pageSize = 10 // for page up/down navigation
+	hScrollStep    = 8 // columns moved per horizontal scroll of diff lines
+	bannerDuration = 10 * time.Second
 )
 
 type Element struct {
@@ -36,6 +43,10 @@ type Element struct {
 	Content   string
 	Status    rune
 	Expanded  bool
+	Row       diffRow // only set for DiffElem in side by side mode
+	Spans     []span  // word diff and syntax colors of a unified diff line, if any
+
+	Highlighted bool // diff lines are syntax highlighted
 }
 
 type Cursor struct {
@@ -90,26 +101,45 @@ func (e *Expanded) ToggleSection(si svn.SectionIdx) {
 }
 
 type Model struct {
-	Width      int
-	Height     int
-	YOffset    int
-	SvnService svn.Service
-	Logger     *slog.Logger
-	Panel      []Element
-	Cursor     Cursor
-	Errs       []string
-	Lines      []string
-	Expanded   Expanded
+	Width         int
+	Height        int
+	YOffset       int
+	SvnService    svn.Service
+	Logger        *slog.Logger
+	Keys          tui.KeyMap
+	Panel         []Element
+	Cursor        Cursor
+	Errs          []string
+	Lines         []string
+	Expanded      Expanded
+	Status        svn.RepoStatus
+	SideBySide    bool
+	WrapLines     bool
+	XOffset       int                   // horizontal scroll of diff lines when not wrapping
+	PageSize      int                   // rows moved by page up/down
+	ScrollPadding int                   // rows kept visible around the cursor when scrolling
+	Collapsed     [svn.NumSections]bool // sections that start collapsed
+	Info          svn.RepoInfo
+	diffs         map[string][]string
+	elemLines     []int // first line in Lines of each Panel element
+	banner        string
+	bannerRev     uint32
+	bannerID      int
+	Menus         []tui.Menu // opened by their prefix keys
+	menu          *tui.Menu  // the open menu, if any
+	updateOpts    *svn.UpdateOptions
 }
 
 func (m *Model) Init() tea.Cmd {
 	m.Logger.Info("StatusModel.Init() called")
 
 	for i := svn.SectionIdx(0); i < svn.NumSections; i++ {
-		m.Expanded.SetSection(i, true)
+		m.Expanded.SetSection(i, !m.Collapsed[i])
 	}
-	m.Expanded.SetSection(svn.SectionUnversioned, false)
 	m.Expanded.Init()
+	m.diffs = make(map[string][]string)
+	m.updateOpts = &svn.UpdateOptions{}
+	m.Menus = append(m.Menus, m.updateMenu())
 
 	return nil
 }
@@ -123,39 +153,65 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 		m.Height = msg.Height - 6 // info panel size 4 + 1 padding top
 	case tui.FetchStatusMsg:
 		return FetchStatusCmd(m.SvnService)
+	case tui.StatusFetchedMsg:
+		return m.SetStatus(msg.Status)
+	case tui.DiffFetchedMsg:
+		m.diffs[msg.Path] = msg.Lines
+		m.Expanded.SetPath(msg.Path, true)
+		m.RefreshStatusPanel()
+		return nil
+	case tui.DiffsFetchedMsg:
+		maps.Copy(m.diffs, msg.Diffs)
+		m.RefreshStatusPanel()
+		return nil
 	case tui.RefreshStatusPanelMsg:
-		return RefreshStatusPanelCmd(m)
+		m.RefreshStatusPanel()
+		return nil
+	case tui.FilesChangedMsg:
+		return FilesChangedCmd(m.SvnService, msg.Paths)
 	case tui.RenderErrorMsg:
 		m.Errs = append(m.Errs, msg.Error())
 		return nil
-	case tea.KeyMsg:
-		keyStr := msg.String()
-		switch keyStr {
-		case "c":
-			return tui.CommitMode
-		case "k", "up":
-			m.Up()
-			return nil
-		case "j", "down":
-			m.Down()
-			return nil
-		case "pgup", "ctrl+u":
-			m.PageUp()
-			return nil
-		case "pgdown", "ctrl+d":
-			m.PageDown()
+	case tui.InfoFetchedMsg:
+		m.Info = msg.Info
+		return nil
+	case tui.CommitSuccessMsg:
+		if msg.Revision == 0 {
+			return m.ShowBanner("Committed.", 0)
+		}
+		m.Info.Revision = msg.Revision
+		return m.ShowBanner(fmt.Sprintf("Committed revision %d.", msg.Revision), msg.Revision)
+	case tui.UpdatedMsg:
+		if msg.Err != nil {
+			m.Errs = append(m.Errs, msg.Err.Error())
 			return nil
-		case "q":
-			return tui.Quit
-		case "s":
-			return m.Stage()
-		case "u":
-			return m.Unstage()
-		case "=":
-			return m.Diff()
-		case "enter":
-			return m.ToggleSectionExpand()
 		}
+		return tea.Batch(m.ShowBanner("Updated the working copy.", 0),
+			FetchStatusCmd(m.SvnService), FetchInfoCmd(m.SvnService))
+	case tui.AmendDoneMsg:
+		return m.ShowBanner(fmt.Sprintf("Changed message of revision %d.", msg.Revision), msg.Revision)
+	case tui.ClearBannerMsg:
+		if msg.ID == m.bannerID {
+			m.banner, m.bannerRev = "", 0
+		}
+		return nil
+	case tea.KeyMsg:
+		if m.menu != nil {
+			cmd, open := m.menu.Update(msg)
+			if !open {
+				m.menu = nil
+			}
+			return cmd
+		}
+		for i := range m.Menus {
+			if key.Matches(msg, m.Menus[i].Prefix) {
+				m.menu = &m.Menus[i]
+				return nil
+			}
+		}
+
+		cmd, _ := tui.Dispatch(msg, m.actions())
+		return cmd
 	default:
 		m.Logger.Info("Unhandled Msg type.", "type", reflect.TypeOf(msg))
 	}
@@ -185,30 +241,33 @@ func clamp(v, low, high int) int {
 	return min(high, max(low, v))
 }
 
-func (m *Model) truncateIfNeeded(content string) string {
+// renderDiffLine renders the spans of a unified diff line. In wrap mode it
+// returns one string per screen line, otherwise a single line scrolled by
+// XOffset and truncated at the panel width.
+func (m *Model) renderDiffLine(spans []span, base, emph lipgloss.Style) []string {
 	// padding on left is 1
 	availableWidth := m.Width - (styles.GutterLen + 1)
-	if availableWidth <= 0 {
-		return content
-	}
-
-	if len(content) <= availableWidth {
-		return content
-	}
+	spans = expandTabs(spans)
 
-	// Reserve 3 chars for "..."
-	if availableWidth <= 3 {
-		return "..."
+	if m.WrapLines {
+		var lines []string
+		for _, line := range wrapSpans(spans, availableWidth) {
+			lines = append(lines, renderSpans(line, 0, "", base, emph))
+		}
+		return lines
 	}
-
-	return content[:availableWidth-3] + "..."
+	return []string{renderSpans(dropSpans(spans, m.XOffset), availableWidth, "...", base, emph)}
 }
 
+// visibleLines scrolls so at least padding lines stay visible on either side
+// of the cursor line, and returns the lines on screen.
 func (m *Model) visibleLines(cursorIdx, padding int) (lines []string) {
+	// more than half the screen would have both sides fighting over it
+	padding = max(0, min(padding, (m.Height-1)/2))
 	if cursorIdx-padding < m.YOffset {
 		m.YOffset = max(0, cursorIdx-padding)
 	} else if cursorIdx+padding >= m.YOffset+m.Height {
-		m.YOffset = min(len(m.Lines)-m.Height, (cursorIdx-(m.Height-1))+2)
+		m.YOffset = max(0, min(len(m.Lines)-m.Height, cursorIdx+padding-(m.Height-1)))
 	}
 
 	if len(m.Lines) > 0 {
@@ -219,21 +278,115 @@ func (m *Model) visibleLines(cursorIdx, padding int) (lines []string) {
 	return lines
 }
 
+func (m *Model) diff(path string) []string {
+	return m.diffs[path]
+}
+
+// diffLen returns the number of rows the diff for path takes up in the
+// current diff mode.
+func (m *Model) diffLen(path string) int {
+	if m.SideBySide {
+		return len(pairDiffLines(m.diff(path)))
+	}
+	return len(m.diff(path))
+}
+
+// visibleDiffPaths returns the diffable paths in the rows last drawn on screen.
+func (m *Model) visibleDiffPaths() []string {
+	var paths []string
+	for i, elem := range m.Panel {
+		// elements added since the last View are assumed to take one line
+		line := i + len(m.headerLines())
+		if i < len(m.elemLines) {
+			line = m.elemLines[i]
+		}
+		if line < m.YOffset || line >= m.YOffset+m.Height {
+			continue
+		}
+		if elem.Type == PathElem && (elem.Status == 'M' || elem.Status == 'A') {
+			paths = append(paths, elem.Content)
+		}
+	}
+	return paths
+}
+
+func (m *Model) PrefetchVisibleDiffs() tea.Cmd {
+	var missing []string
+	for _, path := range m.visibleDiffPaths() {
+		if _, ok := m.diffs[path]; !ok {
+			missing = append(missing, path)
+		}
+	}
+	return FetchDiffsCmd(m.SvnService, missing)
+}
+
+// SetStatus replaces the status snapshot. Diffs that are expanded or on screen
+// are refetched, as the files behind them may have changed, and the rest are
+// dropped so expanding them later goes back to svn.
+func (m *Model) SetStatus(rs svn.RepoStatus) tea.Cmd {
+	m.Status = rs
+	m.RefreshStatusPanel()
+
+	keep := m.visibleDiffPaths()
+	for _, section := range m.Status.Sections {
+		for _, ps := range section.Paths {
+			if m.Expanded.Path(ps.Path) {
+				keep = append(keep, ps.Path)
+			}
+		}
+	}
+	for path := range m.diffs {
+		if !slices.Contains(keep, path) {
+			delete(m.diffs, path)
+		}
+	}
+	return FetchDiffsCmd(m.SvnService, keep)
+}
+
+// ShowBanner shows text about revision rev above the status panel for
+// bannerDuration.
+func (m *Model) ShowBanner(text string, rev uint32) tea.Cmd {
+	m.bannerID++
+	m.bannerRev = rev
+	m.banner = text
+
+	id := m.bannerID
+	return tea.Tick(bannerDuration, func(time.Time) tea.Msg {
+		return tui.ClearBannerMsg{ID: id}
+	})
+}
+
+// headerLines are drawn above the panel elements.
+func (m *Model) headerLines() []string {
+	if m.banner == "" {
+		return m.Errs
+	}
+	banner := styles.Banner.Render(m.banner)
+	if m.bannerRev != 0 {
+		banner += styles.Comment.Render(fmt.Sprintf("  %s copy revision, %s copy URL",
+			m.Keys.Status.CopyRevision.Help().Key, m.Keys.Status.CopyURL.Help().Key))
+	}
+	return append([]string{banner}, m.Errs...)
+}
+
 func (m *Model) View() string {
 	m.Lines = m.Lines[:0]
-	m.Lines = append(m.Lines, m.Errs...)
+	m.Lines = append(m.Lines, m.headerLines()...)
+	m.elemLines = m.elemLines[:0]
 
 	var cursorIdx int
-	for i, elem := range m.Panel {
+	for _, elem := range m.Panel {
 		var b strings.Builder
+		var wrapped []string // continuation lines of a wrapped diff line
 		var isSel bool
 		var gutter string
 		var headingStyle, runeStyle, textStyle lipgloss.Style
-		var addedStyle, removedStyle, diffHeaderStyle lipgloss.Style
+		var addedStyle, removedStyle, diffHeaderStyle, lineNumStyle lipgloss.Style
+		var addedWordStyle, removedWordStyle lipgloss.Style
 		if elem.Type == m.Cursor.ElemType && elem.SectionID == m.Cursor.Section &&
 			elem.PathIdx == m.Cursor.PathIdx && elem.DiffLine == m.Cursor.DiffLine {
 			isSel = true
-			cursorIdx = i
+			cursorIdx = len(m.Lines)
 			gutter = styles.SelGutter
 			headingStyle = styles.SelStatusSectionHeading
 			runeStyle = styles.SelStatusRune
@@ -241,6 +394,9 @@ func (m *Model) View() string {
 			addedStyle = styles.SelAddedStyle
 			removedStyle = styles.SelRemovedStyle
 			diffHeaderStyle = styles.SelDiffHeaderStyle
+			lineNumStyle = styles.SelLineNumber
+			addedWordStyle = styles.SelAddedWordStyle
+			removedWordStyle = styles.SelRemovedWordStyle
 		} else {
 			isSel = false
 			gutter = styles.Gutter
@@ -250,6 +406,9 @@ func (m *Model) View() string {
 			addedStyle = styles.AddedStyle
 			removedStyle = styles.RemovedStyle
 			diffHeaderStyle = styles.DiffHeaderStyle
+			lineNumStyle = styles.LineNumber
+			addedWordStyle = styles.AddedWordStyle
+			removedWordStyle = styles.RemovedWordStyle
 		}
 
 		b.WriteString(gutter)
@@ -263,28 +422,71 @@ func (m *Model) View() string {
 			b.WriteString(headingStyle.Render(") "))
 
 		case PathElem:
+			// padding on left is 1, status rune is 3
+			availableWidth := m.Width - (styles.GutterLen + 1) - 3
 			b.WriteString(runeStyle.Render(" ", string(elem.Status), " "))
-			b.WriteString(textStyle.Render(elem.Content))
+			b.WriteString(textStyle.Render(tui.Truncate(elem.Content, availableWidth, "...")))
 
 		case DiffElem:
-			truncatedContent := m.truncateIfNeeded(elem.Content)
+			// highlighted lines get a background tint so syntax colors can
+			// take over the foreground
+			lineAdded, lineRemoved := addedStyle, removedStyle
+			if elem.Highlighted && isSel {
+				lineAdded, lineRemoved = styles.Selected, styles.Selected
+			} else if elem.Highlighted {
+				lineAdded, lineRemoved = styles.AddedLineStyle, styles.RemovedLineStyle
+			}
+
+			if m.SideBySide {
+				b.WriteString(m.renderSideBySide(elem.Row, sideBySideStyles{
+					text:        textStyle,
+					added:       lineAdded,
+					removed:     lineRemoved,
+					header:      diffHeaderStyle,
+					lineNum:     lineNumStyle,
+					addedWord:   addedWordStyle,
+					removedWord: removedWordStyle,
+				}))
+				break
+			}
+
+			base, emph := textStyle, textStyle
 			switch {
 			case strings.HasPrefix(elem.Content, "@@"):
-				b.WriteString(diffHeaderStyle.Render(truncatedContent))
+				base = diffHeaderStyle
 			case strings.HasPrefix(elem.Content, "+"):
-				b.WriteString(addedStyle.Render(truncatedContent))
+				base, emph = lineAdded, addedWordStyle
 			case strings.HasPrefix(elem.Content, "-"):
-				b.WriteString(removedStyle.Render(truncatedContent))
-			default:
-				b.WriteString(textStyle.Render(truncatedContent))
+				base, emph = lineRemoved, removedWordStyle
 			}
+			lines := m.renderDiffLine(orPlain(elem.Spans, elem.Content), base, emph)
+			b.WriteString(lines[0])
+			wrapped = lines[1:]
 		case BlankElem:
 		default:
 			m.Logger.Error("Invalid element type encountered")
 		}
+		m.elemLines = append(m.elemLines, len(m.Lines))
 		m.Lines = append(m.Lines, b.String())
+		for _, line := range wrapped {
+			m.Lines = append(m.Lines, styles.Gutter+line)
+		}
+	}
+
+	lines := m.visibleLines(cursorIdx, m.ScrollPadding)
+	if m.menu == nil {
+		return strings.Join(lines, "\n")
 	}
-	return strings.Join(m.visibleLines(cursorIdx, styles.ScrollPadding), "\n")
+
+	// the menu takes the bottom of the panel, so drop lines from the top
+	// rather than hide the cursor behind it
+	menu := m.menu.View(m.Width - 3) // padding left 1 + left and right border
+	keep := max(0, m.Height-lipgloss.Height(menu))
+	if cur := cursorIdx - max(0, m.YOffset); cur >= keep && cur < len(lines) {
+		lines = lines[cur-keep+1:]
+	}
+	lines = lines[:min(keep, len(lines))]
+	return strings.Join(append(lines, menu), "\n")
 }
 
 func (m *Model) RefreshStatusPanel() {
@@ -292,7 +494,7 @@ func (m *Model) RefreshStatusPanel() {
 	m.Panel = m.Panel[:0]
 	m.ClampCursor()
 
-	rs := m.SvnService.CurrentStatus()
+	rs := &m.Status
 	for secID, section := range rs.Sections {
 		if len(section.Paths) <= 0 {
 			continue
@@ -333,14 +535,41 @@ func (m *Model) RefreshStatusPanel() {
 				continue
 			}
 
-			for lineNum, diffLine := range m.SvnService.GetDiff(ps.Path) {
+			lines := m.diff(ps.Path)
+			rows := pairDiffLines(lines)
+			syntax := highlightDiff(ps.Path, lines)
+
+			if m.SideBySide {
+				for rowNum, row := range rows {
+					row.highlight(syntax)
+					m.Panel = append(m.Panel,
+						Element{
+							Type:        DiffElem,
+							SectionID:   svn.SectionIdx(secID),
+							PathIdx:     pathIdx,
+							DiffLine:    rowNum,
+							Row:         row,
+							Highlighted: syntax != nil,
+						})
+				}
+				continue
+			}
+
+			words := wordSpans(rows)
+			for lineNum, diffLine := range lines {
+				var lineSyntax []span
+				if syntax != nil {
+					lineSyntax = syntax[lineNum]
+				}
 				m.Panel = append(m.Panel,
 					Element{
-						Type:      DiffElem,
-						SectionID: svn.SectionIdx(secID),
-						PathIdx:   pathIdx,
-						DiffLine:  lineNum,
-						Content:   diffLine,
+						Type:        DiffElem,
+						SectionID:   svn.SectionIdx(secID),
+						PathIdx:     pathIdx,
+						DiffLine:    lineNum,
+						Content:     diffLine,
+						Spans:       unifiedSpans(diffLine, words[lineNum], lineSyntax),
+						Highlighted: syntax != nil,
 					})
 			}
 		}
@@ -360,79 +589,71 @@ func (m *Model) Must(cond bool, msg string) {
 
 func FetchInfoCmd(s svn.Service) tea.Cmd {
 	return func() tea.Msg {
-		if err := s.FetchInfo(); err != nil {
+		info, err := s.FetchInfo()
+		if err != nil {
 			return tui.RenderErrorMsg(err)
 		}
-		return tui.RefreshInfoMsg{}
+		return tui.InfoFetchedMsg{Info: info}
 	}
 }
 
 func FetchStatusCmd(s svn.Service) tea.Cmd {
 	return func() tea.Msg {
-		if err := s.FetchStatus(); err != nil {
+		rs, err := s.FetchStatus()
+		if err != nil {
 			return tui.RenderErrorMsg(err)
 		}
-		return tui.RefreshStatusPanelMsg{}
+		return tui.StatusFetchedMsg{Status: rs}
 	}
 }
 
-func RefreshStatusPanelCmd(m *Model) tea.Cmd {
-	return func() tea.Msg {
-		m.RefreshStatusPanel()
+func FetchDiffsCmd(s svn.Service, paths []string) tea.Cmd {
+	if len(paths) == 0 {
 		return nil
 	}
+	return func() tea.Msg {
+		return tui.DiffsFetchedMsg{Diffs: s.FetchDiffs(paths)}
+	}
 }
 
-func StagePathCmd(m *Model, path string) tea.Cmd {
+func FilesChangedCmd(s svn.Service, paths []string) tea.Cmd {
 	return func() tea.Msg {
-		if err := m.SvnService.StagePath(path); err != nil {
+		for _, p := range paths {
+			s.InvalidateDiff(p)
+		}
+		rs, err := s.FetchStatus()
+		if err != nil {
 			return tui.RenderErrorMsg(err)
 		}
-		m.Expanded.SetPath(path, false)
-		return tui.FetchStatusMsg{}
+		return tui.StatusFetchedMsg{Status: rs}
 	}
 }
 
-func UnstagePathCmd(m *Model, path string) tea.Cmd {
+func StagePathCmd(s svn.Service, path string) tea.Cmd {
 	return func() tea.Msg {
-		if err := m.SvnService.UnstagePath(path); err != nil {
+		if err := s.StagePath(path); err != nil {
 			return tui.RenderErrorMsg(err)
 		}
-		m.Expanded.SetPath(path, false)
 		return tui.FetchStatusMsg{}
 	}
 }
 
-func ToggleSectionExpandCmd(m *Model, si svn.SectionIdx) tea.Cmd {
+func UnstagePathCmd(s svn.Service, path string) tea.Cmd {
 	return func() tea.Msg {
-		m.Expanded.ToggleSection(si)
-		return tui.RefreshStatusPanelMsg{}
+		if err := s.UnstagePath(path); err != nil {
+			return tui.RenderErrorMsg(err)
+		}
+		return tui.FetchStatusMsg{}
 	}
 }
 
-func ToggleDiffExpandCmd(m *Model) tea.Cmd {
+func ExpandDiffCmd(s svn.Service, path string) tea.Cmd {
 	return func() tea.Msg {
-		ps, err := m.SvnService.GetPathStatus(m.Cursor.Section, m.Cursor.PathIdx)
+		lines, err := s.FetchDiff(path)
 		if err != nil {
 			return tui.RenderErrorMsg(err)
 		}
-
-		// case: expanded -> collapsed
-		if m.Expanded.Path(ps.Path) {
-			m.Expanded.TogglePath(ps.Path)
-			m.Cursor.Set(PathElem, m.Cursor.Section, m.Cursor.PathIdx, 0)
-			return tui.RefreshStatusPanelMsg{}
-		}
-
-		// case: collapsed -> expanded
-		if ps.Status != 'M' && ps.Status != 'A' {
-			return nil
-		}
-		if err = m.SvnService.FetchDiff(ps.Path); err != nil {
-			return tui.RenderErrorMsg(err)
-		}
-		m.Expanded.TogglePath(ps.Path)
-		return tui.RefreshStatusPanelMsg{}
+		return tui.DiffFetchedMsg{Path: path, Lines: lines}
 	}
 }
 
@@ -444,12 +665,13 @@ func (m *Model) Stage() tea.Cmd {
 		//TODO: stage whole section
 		return nil
 	case PathElem:
-		ps, err := m.SvnService.GetPathStatus(m.Cursor.Section, m.Cursor.PathIdx)
+		ps, err := m.Status.PathStatus(m.Cursor.Section, m.Cursor.PathIdx)
 		if err != nil {
 			return nil
 		}
 		m.Logger.Info("Returning StagePathCmd", "path", ps.Path)
-		return StagePathCmd(m, ps.Path)
+		m.Expanded.SetPath(ps.Path, false)
+		return StagePathCmd(m.SvnService, ps.Path)
 	}
 
 	return nil
@@ -466,11 +688,12 @@ func (m *Model) Unstage() tea.Cmd {
 		//TODO: stage whole section
 		return nil
 	case PathElem:
-		ps, err := m.SvnService.GetPathStatus(svn.SectionStaged, m.Cursor.PathIdx)
+		ps, err := m.Status.PathStatus(svn.SectionStaged, m.Cursor.PathIdx)
 		if err != nil {
 			return nil
 		}
-		return UnstagePathCmd(m, ps.Path)
+		m.Expanded.SetPath(ps.Path, false)
+		return UnstagePathCmd(m.SvnService, ps.Path)
 	}
 
 	return nil
@@ -481,18 +704,95 @@ func (m *Model) Diff() tea.Cmd {
 	if m.Cursor.ElemType != PathElem && m.Cursor.ElemType != DiffElem {
 		return nil
 	}
-	return ToggleDiffExpandCmd(m)
+
+	ps, err := m.Status.PathStatus(m.Cursor.Section, m.Cursor.PathIdx)
+	if err != nil {
+		m.Errs = append(m.Errs, err.Error())
+		return nil
+	}
+
+	// case: expanded -> collapsed
+	if m.Expanded.Path(ps.Path) {
+		m.Expanded.TogglePath(ps.Path)
+		m.Cursor.Set(PathElem, m.Cursor.Section, m.Cursor.PathIdx, 0)
+		m.RefreshStatusPanel()
+		return nil
+	}
+
+	// case: collapsed -> expanded
+	if ps.Status != 'M' && ps.Status != 'A' {
+		return nil
+	}
+	return ExpandDiffCmd(m.SvnService, ps.Path)
+}
+
+// ToggleSideBySide switches between unified and side by side diffs, keeping
+// the cursor on the same diff line.
+func (m *Model) ToggleSideBySide() {
+	if m.Cursor.ElemType == DiffElem {
+		ps, err := m.Status.PathStatus(m.Cursor.Section, m.Cursor.PathIdx)
+		if err == nil {
+			rows := pairDiffLines(m.diff(ps.Path))
+			if m.SideBySide && m.Cursor.DiffLine < len(rows) {
+				m.Cursor.DiffLine = rows[m.Cursor.DiffLine].unifiedIdx()
+			} else if !m.SideBySide {
+				m.Cursor.DiffLine = sideBySideRow(rows, m.Cursor.DiffLine)
+			}
+		}
+	}
+	m.SideBySide = !m.SideBySide
+	m.RefreshStatusPanel()
+}
+
+// ToggleWrap switches long unified diff lines between wrapping and being
+// truncated. Side by side diffs are always truncated.
+func (m *Model) ToggleWrap() {
+	m.WrapLines = !m.WrapLines
+	m.XOffset = 0
+}
+
+func (m *Model) ScrollLeft() {
+	m.XOffset = max(0, m.XOffset-hScrollStep)
+}
+
+// ScrollRight scrolls diff lines right until the longest expanded line ends
+// at the edge of the panel.
+func (m *Model) ScrollRight() {
+	if m.WrapLines {
+		return
+	}
+
+	widest := 0
+	for _, elem := range m.Panel {
+		if elem.Type != DiffElem {
+			continue
+		}
+		w := ansi.StringWidth(spansText(expandTabs(orPlain(elem.Spans, elem.Content))))
+		if m.SideBySide {
+			w = max(ansi.StringWidth(elem.Row.Old), ansi.StringWidth(elem.Row.New))
+		}
+		widest = max(widest, w)
+	}
+
+	// padding on left is 1
+	availableWidth := m.Width - (styles.GutterLen + 1)
+	if m.SideBySide {
+		availableWidth = (availableWidth-1)/2 - lineNumWidth
+	}
+	m.XOffset = clamp(m.XOffset+hScrollStep, 0, max(0, widest-availableWidth))
 }
 
 func (m *Model) ToggleSectionExpand() tea.Cmd {
 	if m.Cursor.ElemType != HeaderElem {
 		return nil
 	}
-	return ToggleSectionExpandCmd(m, m.Cursor.Section)
+	m.Expanded.ToggleSection(m.Cursor.Section)
+	m.RefreshStatusPanel()
+	return nil
 }
 
 func (m *Model) nextSectionHeader() bool {
-	if next, ok := m.SvnService.CurrentStatus().NextNonEmptySection(m.Cursor.Section); ok {
+	if next, ok := m.Status.NextNonEmptySection(m.Cursor.Section); ok {
 		m.Cursor.Set(HeaderElem, next, 0, 0)
 		return true
 	}
@@ -504,7 +804,7 @@ func (m *Model) DownFromHeader() bool {
 		return false
 	}
 
-	rs := m.SvnService.CurrentStatus()
+	rs := &m.Status
 	// Section is expanded and has entries. Go to first path
 	if m.Expanded.Section(m.Cursor.Section) && rs.Len(m.Cursor.Section) > 0 {
 		m.Cursor.Set(PathElem, m.Cursor.Section, 0, 0)
@@ -520,7 +820,7 @@ func (m *Model) DownFromPath() bool {
 		return false
 	}
 
-	ps, err := m.SvnService.GetPathStatus(m.Cursor.Section, m.Cursor.PathIdx)
+	ps, err := m.Status.PathStatus(m.Cursor.Section, m.Cursor.PathIdx)
 	if err != nil {
 		return false
 	}
@@ -530,7 +830,7 @@ func (m *Model) DownFromPath() bool {
 		return true
 	}
 	// Path not expanded so just go to next path
-	if m.Cursor.PathIdx < m.SvnService.CurrentStatus().Len(m.Cursor.Section)-1 {
+	if m.Cursor.PathIdx < m.Status.Len(m.Cursor.Section)-1 {
 		m.Cursor.Set(PathElem, m.Cursor.Section, m.Cursor.PathIdx+1, 0)
 		return true
 	}
@@ -543,18 +843,17 @@ func (m *Model) DownFromDiff() bool {
 		return false
 	}
 
-	ps, err := m.SvnService.GetPathStatus(m.Cursor.Section, m.Cursor.PathIdx)
+	ps, err := m.Status.PathStatus(m.Cursor.Section, m.Cursor.PathIdx)
 	if err != nil {
 		return false
 	}
 	// Still more lines in current diff
-	diffLines := m.SvnService.GetDiff(ps.Path)
-	if m.Cursor.DiffLine < len(diffLines)-1 {
+	if m.Cursor.DiffLine < m.diffLen(ps.Path)-1 {
 		m.Cursor.Set(DiffElem, m.Cursor.Section, m.Cursor.PathIdx, m.Cursor.DiffLine+1)
 		return true
 	}
 	// No more lines in diff. Navigate to next path if not at final path
-	rs := m.SvnService.CurrentStatus()
+	rs := &m.Status
 	if m.Cursor.PathIdx < rs.Len(m.Cursor.Section)-1 {
 		m.Cursor.Set(PathElem, m.Cursor.Section, m.Cursor.PathIdx+1, 0)
 		return true
@@ -582,7 +881,7 @@ func (m *Model) upFromHeader() bool {
 		return false
 	}
 
-	rs := m.SvnService.CurrentStatus()
+	rs := &m.Status
 	prevSec, ok := rs.PrevNonEmptySection(m.Cursor.Section)
 	// We are at header of the upper most non empty section. Nothing to move up to
 	if !ok {
@@ -596,7 +895,7 @@ func (m *Model) upFromHeader() bool {
 	}
 
 	prevSecSize := rs.Len(prevSec)
-	prevSecLastPath, err := m.SvnService.GetPathStatus(prevSec, prevSecSize-1)
+	prevSecLastPath, err := m.Status.PathStatus(prevSec, prevSecSize-1)
 	if err != nil {
 		return false
 	}
@@ -608,8 +907,7 @@ func (m *Model) upFromHeader() bool {
 	}
 
 	// Path is expanded so move up to last diff line
-	diffLines := m.SvnService.GetDiff(prevSecLastPath.Path)
-	m.Cursor.Set(DiffElem, prevSec, prevSecSize-1, len(diffLines)-1)
+	m.Cursor.Set(DiffElem, prevSec, prevSecSize-1, m.diffLen(prevSecLastPath.Path)-1)
 	return true
 }
 
@@ -618,11 +916,10 @@ func (m *Model) upFromPath() bool {
 		return false
 	}
 
-	prevPath, err := m.SvnService.GetPathStatus(m.Cursor.Section, m.Cursor.PathIdx-1)
+	prevPath, err := m.Status.PathStatus(m.Cursor.Section, m.Cursor.PathIdx-1)
 	if err == nil {
 		if m.Expanded.Path(prevPath.Path) {
-			diffLines := m.SvnService.GetDiff(prevPath.Path)
-			m.Cursor.Set(DiffElem, m.Cursor.Section, m.Cursor.PathIdx-1, max(0, len(diffLines)-1))
+			m.Cursor.Set(DiffElem, m.Cursor.Section, m.Cursor.PathIdx-1, max(0, m.diffLen(prevPath.Path)-1))
 		} else {
 			m.Cursor.Set(PathElem, m.Cursor.Section, m.Cursor.PathIdx-1, 0)
 		}
@@ -663,7 +960,7 @@ func (m *Model) Up() bool {
 
 func (m *Model) PageUp() bool {
 	moved := false
-	for range pageSize {
+	for range m.PageSize {
 		if m.Up() {
 			moved = true
 		} else {
@@ -675,7 +972,7 @@ func (m *Model) PageUp() bool {
 
 func (m *Model) PageDown() bool {
 	moved := false
-	for range pageSize {
+	for range m.PageSize {
 		if m.Down() {
 			moved = true
 		} else {
@@ -686,7 +983,7 @@ func (m *Model) PageDown() bool {
 }
 
 func (m *Model) ClampCursor() {
-	rs := m.SvnService.CurrentStatus()
+	rs := &m.Status
 
 	secLen := rs.Len(m.Cursor.Section)
 	if m.Cursor.PathIdx >= secLen || secLen <= 0 {
@@ -699,4 +996,15 @@ func (m *Model) ClampCursor() {
 			m.Cursor.DiffLine = 0
 		}
 	}
+
+	// diff may have been collapsed or changed length since the cursor was set
+	if m.Cursor.ElemType == DiffElem {
+		ps, _ := rs.PathStatus(m.Cursor.Section, m.Cursor.PathIdx)
+		diffLen := m.diffLen(ps.Path)
+		if !m.Expanded.Path(ps.Path) || diffLen == 0 {
+			m.Cursor.Set(PathElem, m.Cursor.Section, m.Cursor.PathIdx, 0)
+		} else if m.Cursor.DiffLine >= diffLen {
+			m.Cursor.DiffLine = diffLen - 1
+		}
+	}
 }
@@ -0,0 +1,34 @@
+package status
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestVisibleLinesKeepsPadding(t *testing.T) {
+	tests := []struct {
+		name                      string
+		lines, height, padding    int
+		yOffset, cursor, wantYOff int
+	}{
+		{"down by one keeps padding 2", 50, 10, 2, 0, 8, 1},
+		{"down by one keeps padding 4", 50, 10, 4, 0, 6, 1},
+		{"down without padding", 50, 10, 0, 0, 10, 1},
+		{"down stops at the end", 50, 10, 3, 40, 49, 40},
+		{"up keeps padding", 50, 10, 3, 20, 22, 19},
+		{"padding capped at half the screen", 50, 10, 20, 0, 6, 1},
+		{"fewer lines than the screen", 5, 10, 2, 0, 4, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := Model{Height: tt.height, YOffset: tt.yOffset}
+			for i := range tt.lines {
+				m.Lines = append(m.Lines, fmt.Sprint(i))
+			}
+			m.visibleLines(tt.cursor, tt.padding)
+			if m.YOffset != tt.wantYOff {
+				t.Errorf("YOffset = %d, want %d", m.YOffset, tt.wantYOff)
+			}
+		})
+	}
+}
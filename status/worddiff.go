@@ -0,0 +1,85 @@
+package status
+
+import (
+	"regexp"
+	"strings"
+)
+
+const (
+	// skip word diffs whose LCS table would get larger than this
+	maxWordDiffCells = 250_000
+)
+
+var wordRegex = regexp.MustCompile(`[\p{L}\p{N}_]+|\s+|.`)
+
+// wordDiff splits a removed and an added line into spans, marking the words
+// that are not part of their longest common subsequence. It returns nil spans
+// if the lines are too long to compare or have nothing in common.
+func wordDiff(oldLine, newLine string) (oldSpans, newSpans []span) {
+	oldWords := wordRegex.FindAllString(oldLine, -1)
+	newWords := wordRegex.FindAllString(newLine, -1)
+	if len(oldWords)*len(newWords) > maxWordDiffCells {
+		return nil, nil
+	}
+
+	// lcs[i][j] is the LCS length of oldWords[i:] and newWords[j:]
+	lcs := make([][]int, len(oldWords)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(newWords)+1)
+	}
+	for i := len(oldWords) - 1; i >= 0; i-- {
+		for j := len(newWords) - 1; j >= 0; j-- {
+			if oldWords[i] == newWords[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var (
+		oldChanged = make([]bool, len(oldWords))
+		newChanged = make([]bool, len(newWords))
+		common     bool
+	)
+	i, j := 0, 0
+	for i < len(oldWords) && j < len(newWords) {
+		switch {
+		case oldWords[i] == newWords[j]:
+			if strings.TrimSpace(oldWords[i]) != "" {
+				common = true
+			}
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			oldChanged[i] = true
+			i++
+		default:
+			newChanged[j] = true
+			j++
+		}
+	}
+	for ; i < len(oldWords); i++ {
+		oldChanged[i] = true
+	}
+	for ; j < len(newWords); j++ {
+		newChanged[j] = true
+	}
+
+	if !common {
+		return nil, nil
+	}
+	return mergeSpans(oldWords, oldChanged), mergeSpans(newWords, newChanged)
+}
+
+func mergeSpans(words []string, changed []bool) []span {
+	var spans []span
+	for i, w := range words {
+		if len(spans) > 0 && spans[len(spans)-1].Changed == changed[i] {
+			spans[len(spans)-1].Text += w
+			continue
+		}
+		spans = append(spans, span{Text: w, Changed: changed[i]})
+	}
+	return spans
+}
@@ -4,6 +4,7 @@ import (
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 )
 
 const (
@@ -25,35 +26,172 @@ const (
 	boatYellow2  = "#C0A36E" // kanagawa: terminal yellow
 	autumnGreen  = "#76946A" // kanagawa: vcs added
 	autumnRed    = "#C34043" // kanagawa: vcs removed
+	winterGreen  = "#2B3328" // kanagawa: diff add
+	winterRed    = "#43242B" // kanagawa: diff delete
+	crystalBlue  = "#7E9CD8" // kanagawa: function blue
+	springBlue   = "#7FB4CA" // kanagawa: builtin blue
+	peachRed     = "#FF5D62" // kanagawa: preproc red
+	// not part of kanagawa, brighter diff add/delete for intra-line changes
+	winterGreen2 = "#3D4F33"
+	winterRed2   = "#5E2C36"
 
-	// semantic color assignments
-	BgColor         = sumiInk3
-	BgAltColor      = sumiInk4
-	BgSelected      = sumiInk5
-	FgColor         = fujiWhite
-	FgDimColor      = oldWhite
-	CommentColor    = fujiGray
-	LineNumberColor = sumiInk6
-	NumColor        = sakuraPink
-	KeywordColor    = oniViolet
-	SpecialColor    = surimiOrange
-	Special2Color   = waveRed
-
-	DiffHeaderColor    = waveAqua2
-	AddedColor         = autumnGreen
-	RemovedColor       = autumnRed
-	CommitListSelColor = waveAqua2
-
-	ScrollPadding = 2
+	// kanagawa lotus, the light theme of kanagawa.nvim
+	lotusInk1    = "#545464" // lotus: foreground color
+	lotusGray2   = "#716E61" // lotus: foreground dim
+	lotusGray3   = "#8A8980" // lotus: comment color
+	lotusWhite3  = "#F2ECBC" // lotus: bg color
+	lotusWhite4  = "#E7DBA0" // lotus: gutter color
+	lotusWhite5  = "#E4D794" // lotus: cursorline
+	lotusViolet1 = "#A09CAC" // lotus: linenumber
+	lotusViolet4 = "#624C83" // lotus: keyword purple
+	lotusPink    = "#B35B79" // lotus: number pink
+	lotusOrange  = "#CC6D00" // lotus: const color
+	lotusRed     = "#C84053" // lotus: 'return' red
+	lotusRed2    = "#D7474B" // lotus: vcs removed
+	lotusRed3    = "#E82424" // lotus: preproc red
+	lotusRed4    = "#D9A594" // lotus: diff delete
+	lotusGreen   = "#6F894E" // lotus: string green
+	lotusGreen2  = "#6E915F" // lotus: vcs added
+	lotusGreen3  = "#B7D0AE" // lotus: diff add
+	lotusAqua    = "#597B75" // lotus: type color
+	lotusBlue4   = "#4D699B" // lotus: function blue
+	lotusTeal1   = "#4E8CA2" // lotus: builtin blue
+	lotusYellow2 = "#836F4A" // lotus: terminal yellow
+	// not part of lotus, stronger diff add/delete for intra-line changes
+	lotusGreen4 = "#9EC09A"
+	lotusRed5   = "#CF8A7A"
 )
 
+// semantic color assignments of the current theme, see Apply
 var (
+	BgColor            string
+	BgAltColor         string
+	BgSelected         string
+	FgColor            string
+	FgDimColor         string
+	CommentColor       string
+	LineNumberColor    string
+	NumColor           string
+	KeywordColor       string
+	SpecialColor       string
+	Special2Color      string
+	StringColor        string
+	TypeColor          string
+	FunctionColor      string
+	BuiltinColor       string
+	OperatorColor      string
+	PreprocColor       string
+	BorderColor        string
+	CursorColor        string
+	DiffHeaderColor    string
+	AddedColor         string
+	RemovedColor       string
+	BgAddedColor       string
+	BgRemovedColor     string
+	BgAddedWordColor   string
+	BgRemovedWordColor string
+	CommitListSelColor string
+)
+
+var (
+	BaseStyle   lipgloss.Style
+	GutterStyle lipgloss.Style
+	FgDim       lipgloss.Style
+	Comment     lipgloss.Style
+	Number      lipgloss.Style
+	Selected    lipgloss.Style
+
+	border        = lipgloss.RoundedBorder()
+	borderFgStyle lipgloss.Style
+	BorderStyle   lipgloss.Style
+
+	// Info panel
+	InfoHeading lipgloss.Style
+
+	// Status panel
+	StatusSectionHeading    lipgloss.Style
+	SelStatusSectionHeading lipgloss.Style
+	SelComment              lipgloss.Style
+	SelNumber               lipgloss.Style
+	StatusRune              lipgloss.Style
+	SelStatusRune           lipgloss.Style
+
+	// diff colors
+	RemovedStyle    lipgloss.Style
+	SelRemovedStyle lipgloss.Style
+	AddedStyle      lipgloss.Style
+	SelAddedStyle   lipgloss.Style
+
+	// syntax highlighted diff lines
+	RemovedLineStyle lipgloss.Style
+	AddedLineStyle   lipgloss.Style
+
+	// intra-line word changes
+	RemovedWordStyle    lipgloss.Style
+	SelRemovedWordStyle lipgloss.Style
+	AddedWordStyle      lipgloss.Style
+	SelAddedWordStyle   lipgloss.Style
+
+	DiffHeaderStyle    lipgloss.Style
+	SelDiffHeaderStyle lipgloss.Style
+	LineNumber         lipgloss.Style
+	SelLineNumber      lipgloss.Style
+
+	Banner           lipgloss.Style
+	LintErrorStyle   lipgloss.Style
+	LintWarningStyle lipgloss.Style
+
+	// Rendered components
+	GutterLen          = 5
+	Gutter             string
+	SelGutter          string
+	ExpandedHeader     string
+	SelExpandedHeader  string
+	CollapsedHeader    string
+	SelCollapsedHeader string
+)
+
+func init() {
+	Apply(Dark)
+}
+
+// Apply rebuilds every style from t. Styles are copied by value, so it must
+// run before any model keeps one, i.e. before the program is built.
+func Apply(t Theme) {
+	BgColor = t.Bg
+	BgAltColor = t.BgAlt
+	BgSelected = t.BgSelected
+	FgColor = t.Fg
+	FgDimColor = t.FgDim
+	CommentColor = t.Comment
+	LineNumberColor = t.LineNumber
+	NumColor = t.Number
+	KeywordColor = t.Keyword
+	SpecialColor = t.Special
+	Special2Color = t.Special2
+	StringColor = t.String
+	TypeColor = t.Type
+	FunctionColor = t.Function
+	BuiltinColor = t.Builtin
+	OperatorColor = t.Operator
+	PreprocColor = t.Preproc
+	BorderColor = t.Border
+	CursorColor = t.Cursor
+	DiffHeaderColor = t.DiffHeader
+	AddedColor = t.Added
+	RemovedColor = t.Removed
+	BgAddedColor = t.BgAdded
+	BgRemovedColor = t.BgRemoved
+	BgAddedWordColor = t.BgAddedWord
+	BgRemovedWordColor = t.BgRemovedWord
+	CommitListSelColor = t.CommitListSel
+
 	BaseStyle = lipgloss.NewStyle().
-			Background(lipgloss.Color(BgColor)).
-			Foreground(lipgloss.Color(FgColor))
+		Background(lipgloss.Color(BgColor)).
+		Foreground(lipgloss.Color(FgColor))
 
 	GutterStyle = BaseStyle.
-			Background(lipgloss.Color(BgAltColor))
+		Background(lipgloss.Color(BgAltColor))
 
 	FgDim = BaseStyle.
 		Foreground(lipgloss.Color(FgDimColor))
@@ -65,78 +203,106 @@ var (
 		Foreground(lipgloss.Color(NumColor))
 
 	Selected = BaseStyle.
-			Background(lipgloss.Color(BgSelected))
+		Background(lipgloss.Color(BgSelected))
 
-	border        = lipgloss.RoundedBorder()
 	borderFgStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color(waveAqua2))
+		Foreground(lipgloss.Color(BorderColor))
 	BorderStyle = lipgloss.NewStyle().
-			BorderStyle(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color(waveAqua2)).
-			BorderBackground(lipgloss.Color(BgColor))
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color(BorderColor)).
+		BorderBackground(lipgloss.Color(BgColor))
 
-	// Info panel
 	InfoHeading = BaseStyle.
-			Bold(true).
-			Foreground(lipgloss.Color(KeywordColor))
+		Bold(true).
+		Foreground(lipgloss.Color(KeywordColor))
 
-	// Status panel
 	StatusSectionHeading = BaseStyle.
-				Bold(true).
-				Foreground(lipgloss.Color(Special2Color))
+		Bold(true).
+		Foreground(lipgloss.Color(Special2Color))
 
 	SelStatusSectionHeading = StatusSectionHeading.
-				Background(lipgloss.Color(BgSelected))
+		Background(lipgloss.Color(BgSelected))
 
 	SelComment = Comment.
-			Background(lipgloss.Color(BgSelected))
+		Background(lipgloss.Color(BgSelected))
 
 	SelNumber = Number.
-			Background(lipgloss.Color(BgSelected))
+		Background(lipgloss.Color(BgSelected))
 
 	StatusRune = BaseStyle.
-			Foreground(lipgloss.Color(SpecialColor))
+		Foreground(lipgloss.Color(SpecialColor))
 
 	SelStatusRune = StatusRune.
-			Background(lipgloss.Color(BgSelected))
+		Background(lipgloss.Color(BgSelected))
 
-	// diff colors
 	RemovedStyle = BaseStyle.
-			Foreground(lipgloss.Color(RemovedColor))
+		Foreground(lipgloss.Color(RemovedColor))
 
 	SelRemovedStyle = RemovedStyle.
-			Background(lipgloss.Color(BgSelected))
+		Background(lipgloss.Color(BgSelected))
 
 	AddedStyle = BaseStyle.
-			Foreground(lipgloss.Color(AddedColor))
+		Foreground(lipgloss.Color(AddedColor))
 
 	SelAddedStyle = AddedStyle.
-			Background(lipgloss.Color(BgSelected))
+		Background(lipgloss.Color(BgSelected))
+
+	RemovedLineStyle = BaseStyle.
+		Background(lipgloss.Color(BgRemovedColor))
+
+	AddedLineStyle = BaseStyle.
+		Background(lipgloss.Color(BgAddedColor))
+
+	RemovedWordStyle = RemovedStyle.
+		Background(lipgloss.Color(BgRemovedWordColor))
+
+	SelRemovedWordStyle = RemovedWordStyle.
+		Bold(true)
+
+	AddedWordStyle = AddedStyle.
+		Background(lipgloss.Color(BgAddedWordColor))
+
+	SelAddedWordStyle = AddedWordStyle.
+		Bold(true)
 
 	DiffHeaderStyle = BaseStyle.
-			Foreground(lipgloss.Color(DiffHeaderColor))
+		Foreground(lipgloss.Color(DiffHeaderColor))
 
 	SelDiffHeaderStyle = DiffHeaderStyle.
-				Background(lipgloss.Color(BgSelected))
+		Background(lipgloss.Color(BgSelected))
 
-	// Rendered components
-	GutterLen = 5
-	Gutter    = GutterStyle.Render("    ") + BaseStyle.Render(" ")
+	LineNumber = BaseStyle.
+		Foreground(lipgloss.Color(LineNumberColor))
+
+	SelLineNumber = LineNumber.
+		Background(lipgloss.Color(BgSelected))
+
+	Banner = BaseStyle.
+		Bold(true).
+		Foreground(lipgloss.Color(AddedColor))
+
+	LintErrorStyle = BaseStyle.
+		Foreground(lipgloss.Color(RemovedColor))
+
+	LintWarningStyle = BaseStyle.
+		Foreground(lipgloss.Color(SpecialColor))
+
+	Gutter = GutterStyle.Render("    ") + BaseStyle.Render(" ")
 	SelGutter = GutterStyle.
-			Bold(true).
-			Foreground(lipgloss.Color(boatYellow2)).
-			Render(" -> ") + BaseStyle.Render(" ")
+		Bold(true).
+		Foreground(lipgloss.Color(CursorColor)).
+		Render(" -> ") + BaseStyle.Render(" ")
 
-	ExpandedHeader    = Comment.Render("⯆ ")
+	ExpandedHeader = Comment.Render("⯆ ")
 	SelExpandedHeader = Comment.
-				Background(lipgloss.Color(BgSelected)).
-				Render("⯆ ")
+		Background(lipgloss.Color(BgSelected)).
+		Render("⯆ ")
 
-	CollapsedHeader    = Comment.Render("▶ ")
+	CollapsedHeader = Comment.Render("▶ ")
 	SelCollapsedHeader = Comment.
-				Background(lipgloss.Color(BgSelected)).
-				Render("▶ ")
-)
+		Background(lipgloss.Color(BgSelected)).
+		Render("▶ ")
+}
 
 func GetBorderTopWithTitle(title string, width int) string {
 	var b strings.Builder
@@ -144,7 +310,7 @@ func GetBorderTopWithTitle(title string, width int) string {
 	b.WriteString(border.Top)
 	b.WriteString(border.Top)
 	b.WriteString(title)
-	for i := 0; i < width-2-len(title); i++ {
+	for i := 0; i < width-2-ansi.StringWidth(title); i++ {
 		b.WriteString(border.Top)
 	}
 	b.WriteString(border.TopRight)
@@ -0,0 +1,174 @@
+package styles
+
+import (
+	"encoding/json"
+	"log/slog"
+
+	"github.com/DiwashRai/svnty/config"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+const ThemeFileName = "theme.json"
+
+// Theme is a color scheme. Colors are anything lipgloss.Color accepts: hex
+// codes, or ANSI color numbers for terminals that only have 16 or 256.
+type Theme struct {
+	Bg            string `json:"bg"`     // "" keeps the terminal's own background
+	BgAlt         string `json:"bg_alt"` // gutter
+	BgSelected    string `json:"bg_selected"`
+	Fg            string `json:"fg"`
+	FgDim         string `json:"fg_dim"`
+	Comment       string `json:"comment"`
+	LineNumber    string `json:"line_number"`
+	Number        string `json:"number"`
+	Keyword       string `json:"keyword"`
+	Special       string `json:"special"`
+	Special2      string `json:"special2"` // status section headings
+	String        string `json:"string"`
+	Type          string `json:"type"`
+	Function      string `json:"function"`
+	Builtin       string `json:"builtin"`
+	Operator      string `json:"operator"`
+	Preproc       string `json:"preproc"`
+	Border        string `json:"border"`
+	Cursor        string `json:"cursor"` // the arrow in the gutter
+	DiffHeader    string `json:"diff_header"`
+	Added         string `json:"added"`
+	Removed       string `json:"removed"`
+	BgAdded       string `json:"bg_added"`
+	BgRemoved     string `json:"bg_removed"`
+	BgAddedWord   string `json:"bg_added_word"`
+	BgRemovedWord string `json:"bg_removed_word"`
+	CommitListSel string `json:"commit_list_sel"`
+}
+
+// Dark is the kanagawa wave theme of kanagawa.nvim.
+var Dark = Theme{
+	Bg:            sumiInk3,
+	BgAlt:         sumiInk4,
+	BgSelected:    sumiInk5,
+	Fg:            fujiWhite,
+	FgDim:         oldWhite,
+	Comment:       fujiGray,
+	LineNumber:    sumiInk6,
+	Number:        sakuraPink,
+	Keyword:       oniViolet,
+	Special:       surimiOrange,
+	Special2:      waveRed,
+	String:        springGreen,
+	Type:          waveAqua2,
+	Function:      crystalBlue,
+	Builtin:       springBlue,
+	Operator:      boatYellow2,
+	Preproc:       peachRed,
+	Border:        waveAqua2,
+	Cursor:        boatYellow2,
+	DiffHeader:    waveAqua2,
+	Added:         autumnGreen,
+	Removed:       autumnRed,
+	BgAdded:       winterGreen,
+	BgRemoved:     winterRed,
+	BgAddedWord:   winterGreen2,
+	BgRemovedWord: winterRed2,
+	CommitListSel: waveAqua2,
+}
+
+// Light is the kanagawa lotus theme of kanagawa.nvim.
+var Light = Theme{
+	Bg:            lotusWhite3,
+	BgAlt:         lotusWhite4,
+	BgSelected:    lotusWhite5,
+	Fg:            lotusInk1,
+	FgDim:         lotusGray2,
+	Comment:       lotusGray3,
+	LineNumber:    lotusViolet1,
+	Number:        lotusPink,
+	Keyword:       lotusViolet4,
+	Special:       lotusOrange,
+	Special2:      lotusRed,
+	String:        lotusGreen,
+	Type:          lotusAqua,
+	Function:      lotusBlue4,
+	Builtin:       lotusTeal1,
+	Operator:      lotusYellow2,
+	Preproc:       lotusRed3,
+	Border:        lotusAqua,
+	Cursor:        lotusYellow2,
+	DiffHeader:    lotusAqua,
+	Added:         lotusGreen2,
+	Removed:       lotusRed2,
+	BgAdded:       lotusGreen3,
+	BgRemoved:     lotusRed4,
+	BgAddedWord:   lotusGreen4,
+	BgRemovedWord: lotusRed5,
+	CommitListSel: lotusAqua,
+}
+
+var Themes = map[string]Theme{
+	"dark":  Dark,
+	"light": Light,
+}
+
+// ThemeConfig is read from ThemeFileName in the svnty config dir. Name picks
+// a built-in theme, or "auto" to follow the terminal background, and Colors
+// overrides single colors of it.
+type ThemeConfig struct {
+	Name        string          `json:"name"`
+	Transparent bool            `json:"transparent"` // use the terminal background
+	Colors      json.RawMessage `json:"colors"`
+}
+
+// AutoTheme picks the built-in theme matching the terminal background. It
+// queries the terminal, so it must run before the program starts.
+func AutoTheme() Theme {
+	if lipgloss.HasDarkBackground() {
+		return Dark
+	}
+	return Light
+}
+
+// LoadTheme builds the theme from ThemeFileName, falling back to AutoTheme.
+// A non-empty name overrides the name in the file. Terminals with 16 colors
+// or fewer keep their own background, which the nearest ANSI color of a
+// forced one rarely matches.
+func LoadTheme(logger *slog.Logger, name string) Theme {
+	theme := loadTheme(logger, name)
+	if lipgloss.ColorProfile() >= termenv.ANSI {
+		theme.Bg = ""
+	}
+	return theme
+}
+
+func loadTheme(logger *slog.Logger, name string) Theme {
+	tc := readThemeConfig(logger)
+	if name != "" {
+		tc.Name = name
+	}
+
+	theme, ok := Themes[tc.Name]
+	if !ok {
+		if tc.Name != "" && tc.Name != "auto" {
+			logger.Warn("Unknown theme, using default theme", "name", tc.Name)
+		}
+		theme = AutoTheme()
+	}
+	if tc.Colors != nil {
+		if err := json.Unmarshal(tc.Colors, &theme); err != nil {
+			logger.Warn("Failed to parse theme colors", "error", err)
+		}
+	}
+	if tc.Transparent {
+		theme.Bg = ""
+	}
+	return theme
+}
+
+func readThemeConfig(logger *slog.Logger) ThemeConfig {
+	var tc ThemeConfig
+	if _, err := config.ReadFile(logger, ThemeFileName, &tc); err != nil {
+		return ThemeConfig{}
+	}
+	return tc
+}
@@ -0,0 +1,149 @@
+package svn
+
+import (
+	"regexp"
+	"strings"
+)
+
+var bugIDRegex = regexp.MustCompile(`[\w\-]+`)
+
+const (
+	bugIDPlaceholder = "%BUGID%"
+	defaultBugLabel  = "Bug-ID / Issue-Nr:"
+)
+
+// Bugtraq holds the TortoiseSVN issue tracker properties set on the working
+// copy root.
+type Bugtraq struct {
+	URL     string
+	Message string
+	Label   string
+	// one line: the IDs are the capture groups of the expression, the
+	// innermost one of nested groups. two lines: the first finds the issue
+	// references, the second the IDs within them.
+	logRegex []*regexp.Regexp
+	// used instead of logRegex when only bugtraq:message is set, its first
+	// group holds the comma separated IDs
+	messageRegex *regexp.Regexp
+}
+
+// IssueRef is a bug ID found in a log message, Start and End are byte offsets.
+type IssueRef struct {
+	ID         string
+	Start, End int
+}
+
+func parseBugtraq(propsXML PropertiesXML) Bugtraq {
+	var bt Bugtraq
+	for _, target := range propsXML.Targets {
+		for _, prop := range target.Properties {
+			value := strings.TrimSpace(prop.Value)
+			switch prop.Name {
+			case "bugtraq:url":
+				bt.URL = value
+			case "bugtraq:message":
+				bt.Message = value
+			case "bugtraq:label":
+				bt.Label = value
+			case "bugtraq:logregex":
+				for _, line := range strings.Split(value, "\n") {
+					line = strings.TrimSpace(line)
+					if line == "" {
+						continue
+					}
+					re, err := regexp.Compile(line)
+					if err != nil {
+						bt.logRegex = nil
+						break
+					}
+					bt.logRegex = append(bt.logRegex, re)
+				}
+			}
+		}
+	}
+
+	// without a logregex, issue IDs are recognised through the message format
+	if len(bt.logRegex) == 0 && strings.Contains(bt.Message, bugIDPlaceholder) {
+		before, after, _ := strings.Cut(bt.Message, bugIDPlaceholder)
+		bt.messageRegex = regexp.MustCompile(
+			regexp.QuoteMeta(before) + `([\w\-]+(?:,\s*[\w\-]+)*)` + regexp.QuoteMeta(after))
+	}
+	return bt
+}
+
+// HasIssueField reports whether the commit editor should ask for an issue
+// number.
+func (bt Bugtraq) HasIssueField() bool {
+	return strings.Contains(bt.Message, bugIDPlaceholder)
+}
+
+func (bt Bugtraq) FieldLabel() string {
+	if bt.Label == "" {
+		return defaultBugLabel
+	}
+	return bt.Label
+}
+
+// AppendIssue adds the bugtraq:message line for issue to msg.
+func (bt Bugtraq) AppendIssue(msg, issue string) string {
+	issue = strings.TrimSpace(issue)
+	if issue == "" || !bt.HasIssueField() {
+		return msg
+	}
+
+	line := strings.ReplaceAll(bt.Message, bugIDPlaceholder, issue)
+	if strings.TrimSpace(msg) == "" {
+		return line
+	}
+	return strings.TrimRight(msg, "\n") + "\n" + line
+}
+
+func (bt Bugtraq) IssueURL(id string) string {
+	if bt.URL == "" {
+		return ""
+	}
+	return strings.ReplaceAll(bt.URL, bugIDPlaceholder, id)
+}
+
+// IssueRefs returns the issue IDs referenced in msg, in order.
+func (bt Bugtraq) IssueRefs(msg string) []IssueRef {
+	var refs []IssueRef
+	switch len(bt.logRegex) {
+	case 0:
+		if bt.messageRegex == nil {
+			return nil
+		}
+		for _, m := range bt.messageRegex.FindAllStringSubmatchIndex(msg, -1) {
+			for _, id := range bugIDRegex.FindAllStringIndex(msg[m[2]:m[3]], -1) {
+				start, end := m[2]+id[0], m[2]+id[1]
+				refs = append(refs, IssueRef{ID: msg[start:end], Start: start, End: end})
+			}
+		}
+	case 1:
+		for _, m := range bt.logRegex[0].FindAllStringSubmatchIndex(msg, -1) {
+			for g := 2; g+1 < len(m); g += 2 {
+				if m[g] < 0 {
+					continue
+				}
+				ref := IssueRef{ID: msg[m[g]:m[g+1]], Start: m[g], End: m[g+1]}
+				if n := len(refs); n > 0 && ref.Start < refs[n-1].End {
+					// groups open left to right, so one inside the last
+					// is nested in it and replaces it
+					if ref.End <= refs[n-1].End {
+						refs[n-1] = ref
+					}
+					continue
+				}
+				refs = append(refs, ref)
+			}
+		}
+	default:
+		for _, m := range bt.logRegex[0].FindAllStringIndex(msg, -1) {
+			for _, id := range bt.logRegex[1].FindAllStringIndex(msg[m[0]:m[1]], -1) {
+				start, end := m[0]+id[0], m[0]+id[1]
+				refs = append(refs, IssueRef{ID: msg[start:end], Start: start, End: end})
+			}
+		}
+	}
+	return refs
+}
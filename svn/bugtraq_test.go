@@ -0,0 +1,42 @@
+package svn
+
+import (
+	"reflect"
+	"testing"
+)
+
+func bugtraqWithLogRegex(logregex string) Bugtraq {
+	return parseBugtraq(PropertiesXML{Targets: []PropertyTargetXML{{
+		Properties: []PropertyXML{{Name: "bugtraq:logregex", Value: logregex}},
+	}}})
+}
+
+func TestIssueRefs(t *testing.T) {
+	tests := []struct {
+		name     string
+		logregex string
+		msg      string
+		want     []string
+	}{
+		{"one group", `#(\d+)`, "Fix #12 and #34", []string{"12", "34"}},
+		{"nested groups use the innermost", `((?:PRJ|prj)-(\d+))`, "Fix PRJ-12, prj-34", []string{"12", "34"}},
+		{"alternatives", `issue (\d+)|bug (\d+)`, "issue 1, bug 2", []string{"1", "2"}},
+		{"two lines", "[Ii]ssues? #?(\\d+(?:, ?\\d+)*)\n(\\d+)", "Issues 1, 2", []string{"1", "2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			last := 0
+			for _, ref := range bugtraqWithLogRegex(tt.logregex).IssueRefs(tt.msg) {
+				if ref.Start < last {
+					t.Errorf("ref %q overlaps the one before it", ref.ID)
+				}
+				last = ref.End
+				got = append(got, ref.ID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("IssueRefs(%q) = %q, want %q", tt.msg, got, tt.want)
+			}
+		})
+	}
+}
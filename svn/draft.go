@@ -0,0 +1,102 @@
+package svn
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+)
+
+const DraftsDirName = "drafts"
+
+// Draft is the unfinished commit message of one working copy, kept apart
+// from the commit history.
+type Draft struct {
+	Path      string `json:"path"`
+	Message   string `json:"message"`
+	disabled  bool
+	draftFile string
+	logger    *slog.Logger
+}
+
+func NewDraft(logger *slog.Logger, workingCopyPath string) Draft {
+	abs, err := filepath.Abs(workingCopyPath)
+	if err != nil {
+		abs = workingCopyPath
+	}
+
+	svntyDir, err := ConfigDir()
+	if err == nil {
+		err = os.MkdirAll(filepath.Join(svntyDir, DraftsDirName), 0755)
+	}
+	if err != nil {
+		logger.Warn("Failed to get drafts directory, disabling drafts", "error", err)
+		return Draft{Path: abs, disabled: true, logger: logger}
+	}
+
+	// one file per working copy, named by its path
+	sum := sha1.Sum([]byte(abs))
+	draft := Draft{
+		Path:      abs,
+		draftFile: filepath.Join(svntyDir, DraftsDirName, hex.EncodeToString(sum[:8])+".json"),
+		logger:    logger,
+	}
+
+	draft.LoadFromFile()
+	return draft
+}
+
+func (d *Draft) LoadFromFile() {
+	if d.disabled {
+		return
+	}
+
+	data, err := os.ReadFile(d.draftFile)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			d.logger.Warn("Failed to read draft file", "error", err)
+		}
+		return
+	}
+
+	if err := json.Unmarshal(data, d); err != nil {
+		d.logger.Warn("Failed to parse draft file", "error", err)
+	}
+}
+
+// Save writes msg to the draft file, an empty message removes it.
+func (d *Draft) Save(msg string) {
+	if d.disabled || msg == d.Message {
+		return
+	}
+	d.Message = msg
+
+	if msg == "" {
+		if err := os.Remove(d.draftFile); err != nil && !os.IsNotExist(err) {
+			d.logger.Warn("Failed to remove draft file", "error", err, "file", d.draftFile)
+		}
+		return
+	}
+
+	jsonData, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		d.logger.Warn("Failed to marshal draft to JSON", "error", err)
+		return
+	}
+
+	// write and rename so a crash mid-write keeps the previous draft
+	tmpFile := d.draftFile + ".tmp"
+	if err := os.WriteFile(tmpFile, jsonData, 0644); err != nil {
+		d.logger.Warn("Failed to write draft file", "error", err, "file", tmpFile)
+		return
+	}
+	if err := os.Rename(tmpFile, d.draftFile); err != nil {
+		d.logger.Warn("Failed to write draft file", "error", err, "file", d.draftFile)
+	}
+}
+
+func (d *Draft) Clear() {
+	d.Save("")
+}
@@ -2,6 +2,7 @@ package svn
 
 import (
 	"encoding/json"
+	"fmt"
 	"log/slog"
 	"os"
 	"path/filepath"
@@ -9,34 +10,47 @@ import (
 )
 
 const (
-	MaxHistorySize  = 10
-	HistoryFileName = "commit_history.json"
+	DefaultHistorySize = 100
+	HistoryFileName    = "commit_history.json"
+	HistoryDirName     = "history"
 )
 
+// CommitHistory starts out in the global HistoryFileName and moves to a file
+// per repository UUID once UseRepository is called.
 type CommitHistory struct {
 	Messages    []string `json:"messages"`
+	maxSize     int
 	disabled    bool
 	historyFile string
 	logger      *slog.Logger
 }
 
-func NewCommitHistory(logger *slog.Logger) CommitHistory {
+// ConfigDir returns the svnty directory under the user config directory,
+// creating it if needed.
+func ConfigDir() (string, error) {
 	configDir, err := os.UserConfigDir()
 	if err != nil {
-		logger.Warn("Failed to get user config directory, disabling history", "error", err)
-		return CommitHistory{
-			Messages: []string{},
-			disabled: true,
-			logger:   logger,
-		}
+		return "", fmt.Errorf("failed to get user config directory: %w", err)
 	}
 
 	svntyDir := filepath.Join(configDir, "svnty")
-	err = os.MkdirAll(svntyDir, 0755)
+	if err := os.MkdirAll(svntyDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create config directory: %w", err)
+	}
+	return svntyDir, nil
+}
+
+func NewCommitHistory(logger *slog.Logger, maxSize int) CommitHistory {
+	if maxSize <= 0 {
+		maxSize = DefaultHistorySize
+	}
+
+	svntyDir, err := ConfigDir()
 	if err != nil {
-		logger.Warn("Failed to create config directory, disabling history", "error", err)
+		logger.Warn("Failed to get config directory, disabling history", "error", err)
 		return CommitHistory{
 			Messages: []string{},
+			maxSize:  maxSize,
 			disabled: true,
 			logger:   logger,
 		}
@@ -45,6 +59,7 @@ func NewCommitHistory(logger *slog.Logger) CommitHistory {
 	historyFile := filepath.Join(svntyDir, HistoryFileName)
 	history := CommitHistory{
 		Messages:    []string{},
+		maxSize:     maxSize,
 		disabled:    false,
 		historyFile: historyFile,
 		logger:      logger,
@@ -54,6 +69,51 @@ func NewCommitHistory(logger *slog.Logger) CommitHistory {
 	return history
 }
 
+// UseRepository switches to the history file of the repository with the given
+// UUID. A repository without its own history yet starts empty, except the
+// first one opened after upgrading, which takes over the messages of the
+// global HistoryFileName. That file is then renamed so it is only taken once.
+func (ch *CommitHistory) UseRepository(uuid string) {
+	if ch.disabled || uuid == "" {
+		return
+	}
+
+	svntyDir, err := ConfigDir()
+	if err != nil {
+		ch.logger.Warn("Failed to get config directory", "error", err)
+		return
+	}
+
+	historyDir := filepath.Join(svntyDir, HistoryDirName)
+	if err := os.MkdirAll(historyDir, 0755); err != nil {
+		ch.logger.Warn("Failed to create history directory", "error", err)
+		return
+	}
+
+	historyFile := filepath.Join(historyDir, uuid+".json")
+	if historyFile == ch.historyFile {
+		return
+	}
+	globalFile := filepath.Join(svntyDir, HistoryFileName)
+	fromGlobal := ch.historyFile == globalFile
+	ch.historyFile = historyFile
+
+	if _, err := os.Stat(historyFile); err == nil {
+		ch.Messages = []string{}
+		ch.LoadFromFile()
+		return
+	}
+	if !fromGlobal || len(ch.Messages) == 0 {
+		ch.Messages = []string{}
+		return
+	}
+
+	ch.SaveToFile()
+	if err := os.Rename(globalFile, globalFile+".migrated"); err != nil {
+		ch.logger.Warn("Failed to retire global history file", "error", err)
+	}
+}
+
 func (ch *CommitHistory) LoadFromFile() {
 	if ch.disabled {
 		return
@@ -71,6 +131,9 @@ func (ch *CommitHistory) LoadFromFile() {
 	if err != nil {
 		ch.logger.Warn("Failed to parse history file", "error", err)
 	}
+	if len(ch.Messages) > ch.maxSize {
+		ch.Messages = ch.Messages[:ch.maxSize]
+	}
 }
 
 func (ch *CommitHistory) GetHistory() []string {
@@ -83,9 +146,26 @@ func (ch *CommitHistory) AddMessage(msg string) {
 	}
 
 	ch.Messages = slices.Insert(ch.Messages, 0, msg)
-	if len(ch.Messages) > MaxHistorySize {
-		ch.Messages = ch.Messages[:MaxHistorySize]
+	if len(ch.Messages) > ch.maxSize {
+		ch.Messages = ch.Messages[:ch.maxSize]
+	}
+}
+
+// ImportMessages adds older messages after the current history, skipping
+// duplicates. It returns how many were added.
+func (ch *CommitHistory) ImportMessages(msgs []string) int {
+	added := 0
+	for _, msg := range msgs {
+		if len(ch.Messages) >= ch.maxSize {
+			break
+		}
+		if msg == "" || slices.Contains(ch.Messages, msg) {
+			continue
+		}
+		ch.Messages = append(ch.Messages, msg)
+		added++
 	}
+	return added
 }
 
 func (ch *CommitHistory) SaveToFile() {
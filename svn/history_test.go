@@ -0,0 +1,66 @@
+package svn
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func useTempConfigDir(t *testing.T) string {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("HOME", dir)
+	t.Setenv("AppData", dir)
+	svntyDir, err := ConfigDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return svntyDir
+}
+
+func TestUseRepositoryMigratesGlobalHistoryOnce(t *testing.T) {
+	svntyDir := useTempConfigDir(t)
+	logger := slog.New(slog.DiscardHandler)
+
+	global := NewCommitHistory(logger, 10)
+	global.AddMessage("old message")
+	global.SaveToFile()
+
+	first := NewCommitHistory(logger, 10)
+	first.UseRepository("uuid-a")
+	if want := []string{"old message"}; !reflect.DeepEqual(first.GetHistory(), want) {
+		t.Errorf("first repository history = %q, want %q", first.GetHistory(), want)
+	}
+	if _, err := os.Stat(filepath.Join(svntyDir, HistoryFileName)); !os.IsNotExist(err) {
+		t.Errorf("the global history file was not retired: %v", err)
+	}
+
+	second := NewCommitHistory(logger, 10)
+	second.UseRepository("uuid-b")
+	if len(second.GetHistory()) != 0 {
+		t.Errorf("second repository history = %q, want it empty", second.GetHistory())
+	}
+
+	again := NewCommitHistory(logger, 10)
+	again.UseRepository("uuid-a")
+	if want := []string{"old message"}; !reflect.DeepEqual(again.GetHistory(), want) {
+		t.Errorf("reopened repository history = %q, want %q", again.GetHistory(), want)
+	}
+}
+
+func TestUseRepositorySwitchStartsEmpty(t *testing.T) {
+	useTempConfigDir(t)
+	logger := slog.New(slog.DiscardHandler)
+
+	ch := NewCommitHistory(logger, 10)
+	ch.UseRepository("uuid-a")
+	ch.AddMessage("message of a")
+	ch.SaveToFile()
+
+	ch.UseRepository("uuid-b")
+	if len(ch.GetHistory()) != 0 {
+		t.Errorf("history after switching = %q, want it empty", ch.GetHistory())
+	}
+}
@@ -0,0 +1,66 @@
+package svn
+
+import (
+	"encoding/xml"
+	"path/filepath"
+	"strings"
+)
+
+// IgnoreRules maps an absolute directory path to the svn:ignore patterns set
+// on it. Patterns only apply to the direct children of that directory.
+type IgnoreRules map[string][]string
+
+func (ir IgnoreRules) Match(path string) bool {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+
+	for _, pattern := range ir[filepath.Dir(abs)] {
+		if ok, _ := filepath.Match(pattern, filepath.Base(abs)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func parseIgnoreRules(propsXML PropertiesXML) IgnoreRules {
+	rules := make(IgnoreRules)
+	for _, target := range propsXML.Targets {
+		abs, err := filepath.Abs(target.Path)
+		if err != nil {
+			continue
+		}
+		for _, prop := range target.Properties {
+			if prop.Name != "svn:ignore" {
+				continue
+			}
+			for _, line := range strings.Split(prop.Value, "\n") {
+				line = strings.TrimSpace(line)
+				if line != "" {
+					rules[abs] = append(rules[abs], line)
+				}
+			}
+		}
+	}
+	return rules
+}
+
+// SVN PROPGET XML Structs
+
+type PropertiesXML struct {
+	XMLName xml.Name            `xml:"properties"`
+	Targets []PropertyTargetXML `xml:"target"`
+}
+
+type PropertyTargetXML struct {
+	XMLName    xml.Name      `xml:"target"`
+	Path       string        `xml:"path,attr"`
+	Properties []PropertyXML `xml:"property"`
+}
+
+type PropertyXML struct {
+	XMLName xml.Name `xml:"property"`
+	Name    string   `xml:"name,attr"`
+	Value   string   `xml:",chardata"`
+}
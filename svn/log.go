@@ -0,0 +1,24 @@
+package svn
+
+import "encoding/xml"
+
+type LogEntry struct {
+	Revision uint32
+	Author   string
+	Message  string
+}
+
+// SVN LOG XML Structs
+
+type LogXML struct {
+	XMLName xml.Name      `xml:"log"`
+	Entries []LogEntryXML `xml:"logentry"`
+}
+
+type LogEntryXML struct {
+	XMLName  xml.Name `xml:"logentry"`
+	Revision uint32   `xml:"revision,attr"`
+	Author   string   `xml:"author"`
+	Date     string   `xml:"date"`
+	Msg      string   `xml:"msg"`
+}
@@ -1,49 +1,78 @@
 package svn
 
+import "context"
+
 type MockService struct {
 }
 
 func (svc *MockService) Init() {}
 
-func (svc *MockService) CurrentInfo() RepoInfo {
+func (svc *MockService) FetchInfo() (RepoInfo, error) {
 	return RepoInfo{
 		WorkingPath: "C:/Code/GitHub/textual-test/",
 		RemoteURL:   "https://svn.riouxsvn.com/textual-test",
 		Revision:    64,
-	}
+	}, nil
 }
 
-func (svc *MockService) FetchInfo() error {
-	return nil
+func (svc *MockService) FetchStatus() (RepoStatus, error) {
+	return NewRepoStatus(), nil
 }
 
-func (svc *MockService) CurrentStatus() *RepoStatus {
+func (svc *MockService) StagePath(path string) error {
 	return nil
 }
 
-func (svc *MockService) FetchStatus() error {
+func (svc *MockService) UnstagePath(path string) error {
 	return nil
 }
 
-func (svc *MockService) StagePath(path string) error {
-	return nil
+func (svc *MockService) FetchDiff(path string) ([]string, error) {
+	return nil, nil
 }
 
-func (svc *MockService) UnstagePath(path string) error {
+func (svc *MockService) FetchDiffs(paths []string) map[string][]string {
 	return nil
 }
 
-func (svc *MockService) FetchDiff(path string) error {
-	return nil
+func (svc *MockService) InvalidateDiff(path string) {}
+
+func (svc *MockService) FetchIgnoreRules() (IgnoreRules, error) {
+	return IgnoreRules{}, nil
 }
 
-func (svc *MockService) GetDiff(path string) []string {
+func (svc *MockService) FetchBugtraq() (Bugtraq, error) {
+	return Bugtraq{}, nil
+}
+
+func (svc *MockService) FetchLogMessages(ctx context.Context, author string, limit int) ([]string, error) {
+	return nil, nil
+}
+
+func (svc *MockService) FetchLastCommit(ctx context.Context, author string) (LogEntry, error) {
+	return LogEntry{Revision: 64, Author: author, Message: "Mock commit message"}, nil
+}
+
+func (svc *MockService) FetchUsername(repoURL string) (string, error) {
+	return "", nil
+}
+
+func (svc *MockService) SetLogMessage(rev uint32, msg string) error {
 	return nil
 }
 
-func (svc *MockService) GetPathStatus(si SectionIdx, idx int) (PathStatus, error) {
-	return PathStatus{}, nil
+func (svc *MockService) PreflightCheck() ([]PreflightProblem, error) {
+	return nil, nil
 }
-func (svc *MockService) CommitStaged(msg string) error {
+
+func (svc *MockService) UpdateWorkingCopy(opts UpdateOptions) error {
 	return nil
 }
+
+func (svc *MockService) ResolvePaths(paths []string) error {
+	return nil
+}
+
+func (svc *MockService) CommitStaged(msg string, opts CommitOptions) (uint32, error) {
+	return 65, nil
+}
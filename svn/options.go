@@ -0,0 +1,54 @@
+package svn
+
+// Depths accepted by svn commit and update --depth, "" leaves svn's default.
+var Depths = []string{"", "empty", "files", "immediates", "infinity"}
+
+// CommitOptions are extra flags for svn commit.
+type CommitOptions struct {
+	KeepChangelists  bool
+	Depth            string
+	IncludeExternals bool
+	NoUnlock         bool
+}
+
+func (o CommitOptions) Args() []string {
+	var args []string
+	if o.KeepChangelists {
+		args = append(args, "--keep-changelists")
+	}
+	if o.Depth != "" {
+		args = append(args, "--depth", o.Depth)
+	}
+	if o.IncludeExternals {
+		args = append(args, "--include-externals")
+	}
+	if o.NoUnlock {
+		args = append(args, "--no-unlock")
+	}
+	return args
+}
+
+// Accepts are the conflict resolutions of svn update --accept that need no
+// prompt, "" leaves conflicts postponed.
+var Accepts = []string{"", "mine-conflict", "theirs-conflict", "mine-full", "theirs-full"}
+
+// UpdateOptions are extra flags for svn update.
+type UpdateOptions struct {
+	IgnoreExternals bool
+	Depth           string
+	Accept          string
+}
+
+func (o UpdateOptions) Args() []string {
+	var args []string
+	if o.IgnoreExternals {
+		args = append(args, "--ignore-externals")
+	}
+	if o.Depth != "" {
+		args = append(args, "--depth", o.Depth)
+	}
+	if o.Accept != "" {
+		args = append(args, "--accept", o.Accept)
+	}
+	return args
+}
@@ -0,0 +1,107 @@
+package svn
+
+import (
+	"bytes"
+	"os"
+	"slices"
+)
+
+type ProblemKind int
+
+const (
+	ProblemConflicted ProblemKind = iota
+	ProblemTreeConflicted
+	ProblemMissing
+	ProblemOutOfDate
+)
+
+func (k ProblemKind) String() string {
+	switch k {
+	case ProblemConflicted:
+		return "conflicted"
+	case ProblemTreeConflicted:
+		return "tree conflict"
+	case ProblemMissing:
+		return "missing"
+	case ProblemOutOfDate:
+		return "out of date"
+	}
+	return "unknown"
+}
+
+// Resolvable reports whether svn resolve can clear the problem. A text
+// conflict should only be marked resolved once its markers are edited out,
+// see UnresolvedPaths.
+func (k ProblemKind) Resolvable() bool {
+	return k == ProblemConflicted || k == ProblemTreeConflicted
+}
+
+// PreflightProblem is a staged path that would make svn commit fail.
+type PreflightProblem struct {
+	Path string
+	Kind ProblemKind
+}
+
+func parsePreflight(statusXML StatusXML) []PreflightProblem {
+	var problems []PreflightProblem
+	for _, cl := range statusXML.ChangeLists {
+		if cl.Name != "staged" {
+			continue
+		}
+		for _, entry := range cl.Entries {
+			wc := entry.WCStatus
+			switch {
+			case wc.Status == "conflicted", wc.Props == "conflicted":
+				problems = append(problems, PreflightProblem{Path: entry.Path, Kind: ProblemConflicted})
+			case wc.Status == "missing":
+				problems = append(problems, PreflightProblem{Path: entry.Path, Kind: ProblemMissing})
+			}
+			if wc.TreeConflicted {
+				problems = append(problems, PreflightProblem{Path: entry.Path, Kind: ProblemTreeConflicted})
+			}
+			if rs := entry.ReposStatus; rs != nil && (rs.Status != "none" || rs.Props != "none") {
+				problems = append(problems, PreflightProblem{Path: entry.Path, Kind: ProblemOutOfDate})
+			}
+		}
+	}
+	return problems
+}
+
+// ResolvablePaths returns the conflicted paths among problems, once each.
+func ResolvablePaths(problems []PreflightProblem) []string {
+	var paths []string
+	for _, p := range problems {
+		if p.Kind.Resolvable() && !slices.Contains(paths, p.Path) {
+			paths = append(paths, p.Path)
+		}
+	}
+	return paths
+}
+
+// UnresolvedPaths returns the text conflicted paths among problems whose
+// working file still holds conflict markers, or cannot be read. Marking them
+// resolved would commit the markers.
+func UnresolvedPaths(problems []PreflightProblem) []string {
+	var paths []string
+	for _, p := range problems {
+		if p.Kind == ProblemConflicted && !slices.Contains(paths, p.Path) && hasConflictMarkers(p.Path) {
+			paths = append(paths, p.Path)
+		}
+	}
+	return paths
+}
+
+// hasConflictMarkers looks for the lines svn puts around the two sides of a
+// text conflict.
+func hasConflictMarkers(path string) bool {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return true
+	}
+	var start, end bool
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		start = start || bytes.HasPrefix(line, []byte("<<<<<<< "))
+		end = end || bytes.HasPrefix(line, []byte(">>>>>>> "))
+	}
+	return start && end
+}
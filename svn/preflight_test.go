@@ -0,0 +1,33 @@
+package svn
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestUnresolvedPaths(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, data string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	marked := write("marked.go", "a\n<<<<<<< .working\nb\n=======\nc\n>>>>>>> .merge-right.r5\n")
+	edited := write("edited.go", "a\nb\n")
+	tree := write("tree.go", "<<<<<<< .working\n>>>>>>> .merge-right.r5\n")
+
+	problems := []PreflightProblem{
+		{Path: marked, Kind: ProblemConflicted},
+		{Path: edited, Kind: ProblemConflicted},
+		{Path: tree, Kind: ProblemTreeConflicted},
+		{Path: filepath.Join(dir, "gone.go"), Kind: ProblemConflicted},
+	}
+	want := []string{marked, filepath.Join(dir, "gone.go")}
+	if got := UnresolvedPaths(problems); !reflect.DeepEqual(got, want) {
+		t.Errorf("UnresolvedPaths() = %q, want %q", got, want)
+	}
+}
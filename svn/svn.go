@@ -2,75 +2,121 @@ package svn
 
 import (
 	"bytes"
+	"context"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"log/slog"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"os/exec"
 )
 
+const (
+	maxDiffWorkers = 4   // concurrent svn diff processes used for prefetching
+	logPageSize    = 200 // commits read per svn log when searching by author
+	maxLogPages    = 5   // pages read before giving up on finding more
+)
+
+// Service methods are called from tea.Cmd goroutines. They hand back fresh
+// values that callers own, and must not share mutable state with the models.
 type Service interface {
 	Init()
-	CurrentInfo() RepoInfo
-	FetchInfo() error
-	CurrentStatus() *RepoStatus
-	FetchStatus() error
+	FetchInfo() (RepoInfo, error)
+	FetchStatus() (RepoStatus, error)
 	StagePath(string) error
 	UnstagePath(string) error
-	FetchDiff(string) error
-	GetDiff(string) []string
-	GetPathStatus(SectionIdx, int) (PathStatus, error)
-	CommitStaged(string) error
+	FetchDiff(string) ([]string, error)
+	FetchDiffs([]string) map[string][]string
+	InvalidateDiff(string)
+	FetchIgnoreRules() (IgnoreRules, error)
+	FetchBugtraq() (Bugtraq, error)
+	FetchLogMessages(ctx context.Context, author string, limit int) ([]string, error)
+	FetchLastCommit(ctx context.Context, author string) (LogEntry, error)
+	FetchUsername(repoURL string) (string, error)
+	SetLogMessage(rev uint32, msg string) error
+	PreflightCheck() ([]PreflightProblem, error)
+	UpdateWorkingCopy(opts UpdateOptions) error
+	ResolvePaths([]string) error
+	CommitStaged(msg string, opts CommitOptions) (uint32, error)
 }
 
 type RealService struct {
 	WorkingCopyPath string
-	RepoInfo        RepoInfo
-	RepoStatus      RepoStatus
+	Binary          string // the svn executable, "svn" from PATH when empty
 	Logger          *slog.Logger
-	diffCache       map[string][]string
+	diffMu          sync.Mutex
+	diffCache       map[string]cachedDiff
+	diffWorkers     chan struct{}
 }
 
-func (svc *RealService) Init() {
-	svc.Logger.Info("RealService.Init()")
+// cachedDiff remembers the size and mtime of the file when its diff was taken
+// so edits made since then are detected without running svn again.
+type cachedDiff struct {
+	lines   []string
+	modTime time.Time
+	size    int64
+}
 
-	for i := SectionIdx(0); i < NumSections; i++ {
-		svc.RepoStatus.Sections[i].Title = SectionTitles[i]
+func statFile(path string) (time.Time, int64) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, -1
 	}
-	if svc.diffCache == nil {
-		svc.diffCache = make(map[string][]string)
+	return info.ModTime(), info.Size()
+}
+
+func (cd cachedDiff) fresh(path string) bool {
+	modTime, size := statFile(path)
+	return cd.modTime.Equal(modTime) && cd.size == size
+}
+
+func (svc *RealService) binary() string {
+	if svc.Binary == "" {
+		return "svn"
 	}
+	return svc.Binary
 }
 
-func (svc *RealService) CurrentInfo() RepoInfo {
-	return svc.RepoInfo
+func (svc *RealService) Init() {
+	svc.Logger.Info("RealService.Init()")
+
+	if svc.diffCache == nil {
+		svc.diffCache = make(map[string]cachedDiff)
+	}
+	if svc.diffWorkers == nil {
+		svc.diffWorkers = make(chan struct{}, maxDiffWorkers)
+	}
 }
 
-func (svc *RealService) FetchInfo() error {
+func (svc *RealService) FetchInfo() (RepoInfo, error) {
 	cmd := exec.Command(
-		"svn", "--non-interactive",
+		svc.binary(), "--non-interactive",
 		"info", svc.WorkingCopyPath, "--xml")
 
 	out, err := cmd.Output()
 	if err != nil {
-		return fmt.Errorf("Error running svn info: %w", err)
+		return RepoInfo{}, fmt.Errorf("Error running svn info: %w", err)
 	}
 
 	var infoXML InfoXML
 	if err := xml.Unmarshal(out, &infoXML); err != nil {
-		return fmt.Errorf("error unmarshalling svn info: %w", err)
+		return RepoInfo{}, fmt.Errorf("error unmarshalling svn info: %w", err)
 	}
 
-	svc.RepoInfo.WorkingPath = infoXML.Entry.WCInfo.WCAbspath
-	svc.RepoInfo.RemoteURL = infoXML.Entry.URL
-	svc.RepoInfo.Revision = infoXML.Entry.Revision
-
-	return nil
-}
-
-func (svc *RealService) CurrentStatus() *RepoStatus {
-	return &svc.RepoStatus
+	return RepoInfo{
+		WorkingPath: infoXML.Entry.WCInfo.WCAbspath,
+		RemoteURL:   infoXML.Entry.URL,
+		Revision:    infoXML.Entry.Revision,
+		UUID:        infoXML.Entry.Repository.UUID,
+	}, nil
 }
 
 func entryToPathStatus(entry StatusEntryXML) (PathStatus, error) {
@@ -82,38 +128,38 @@ func entryToPathStatus(entry StatusEntryXML) (PathStatus, error) {
 	return PathStatus{Path: entry.Path, Status: statusRune}, nil
 }
 
-func (svc *RealService) FetchStatus() error {
-	svc.RepoStatus.Clear()
+func (svc *RealService) FetchStatus() (RepoStatus, error) {
+	rs := NewRepoStatus()
 
 	cmd := exec.Command(
-		"svn", "--non-interactive",
+		svc.binary(), "--non-interactive",
 		"status", svc.WorkingCopyPath, "--xml")
 
 	out, err := cmd.Output()
 	if err != nil {
-		return fmt.Errorf("error running svn status: %w", err)
+		return rs, fmt.Errorf("error running svn status: %w", err)
 	}
 
 	var statusXML StatusXML
 	if err := xml.Unmarshal(out, &statusXML); err != nil {
-		return fmt.Errorf("error unmarshalling svn status: %w", err)
+		return rs, fmt.Errorf("error unmarshalling svn status: %w", err)
 	}
 
 	for _, entry := range statusXML.Target.Entries {
 		ps, err := entryToPathStatus(entry)
 		if err != nil {
-			return err
+			return rs, err
 		}
 
 		switch entry.WCStatus.Status {
 		case "unversioned":
-			svc.RepoStatus.Append(SectionUnversioned, ps)
+			rs.Append(SectionUnversioned, ps)
 		case "added", "deleted", "modified", "missing", "replaced":
-			svc.RepoStatus.Append(SectionUnstaged, ps)
+			rs.Append(SectionUnstaged, ps)
 		case "conflicted", "external", "obstructed":
-			svc.RepoStatus.Append(SectionIssues, ps)
+			rs.Append(SectionIssues, ps)
 		case "ignored":
-			svc.RepoStatus.Append(SectionIgnored, ps)
+			rs.Append(SectionIgnored, ps)
 		}
 	}
 
@@ -122,24 +168,34 @@ func (svc *RealService) FetchStatus() error {
 			for _, entry := range cl.Entries {
 				ps, err := entryToPathStatus(entry)
 				if err != nil {
-					return err
+					return rs, err
 				}
-				svc.RepoStatus.Append(SectionStaged, ps)
+				rs.Append(SectionStaged, ps)
 			}
 		}
 	}
 
-	return nil
+	svc.pruneDiffCache(rs)
+	return rs, nil
 }
 
-func (svc *RealService) GetPathStatus(si SectionIdx, idx int) (PathStatus, error) {
-	if si < 0 || si >= NumSections {
-		return PathStatus{}, fmt.Errorf("GetPath with out of bounds section id called")
+// pruneDiffCache drops diffs for paths that are no longer modified, such as
+// after a commit or revert, and for files edited since the diff was taken.
+func (svc *RealService) pruneDiffCache(rs RepoStatus) {
+	modified := make(map[string]bool)
+	for _, section := range rs.Sections {
+		for _, ps := range section.Paths {
+			modified[ps.Path] = true
+		}
 	}
-	if idx < 0 || idx >= len(svc.RepoStatus.Sections[si].Paths) {
-		return PathStatus{}, fmt.Errorf("GetPath with out of bounds idx called")
+
+	svc.diffMu.Lock()
+	defer svc.diffMu.Unlock()
+	for path, cached := range svc.diffCache {
+		if !modified[path] || !cached.fresh(path) {
+			delete(svc.diffCache, path)
+		}
 	}
-	return svc.RepoStatus.Sections[si].Paths[idx], nil
 }
 
 func (svc *RealService) StagePath(path string) error {
@@ -148,7 +204,7 @@ func (svc *RealService) StagePath(path string) error {
 		return nil
 	}
 	cmd := exec.Command(
-		"svn", "--non-interactive",
+		svc.binary(), "--non-interactive",
 		"changelist", "staged", path)
 
 	_, err := cmd.Output()
@@ -165,7 +221,7 @@ func (svc *RealService) UnstagePath(path string) error {
 		return nil
 	}
 	cmd := exec.Command(
-		"svn", "--non-interactive",
+		svc.binary(), "--non-interactive",
 		"changelist", "--remove", path)
 
 	_, err := cmd.Output()
@@ -176,83 +232,431 @@ func (svc *RealService) UnstagePath(path string) error {
 	return nil
 }
 
-func (svc *RealService) FetchDiff(path string) error {
+func (svc *RealService) FetchDiff(path string) ([]string, error) {
 	svc.Logger.Info("FetchDiff called", "path", path)
 	if path == "" {
-		return fmt.Errorf("Empty path provided to diff")
+		return nil, fmt.Errorf("Empty path provided to diff")
 	}
 
-	if _, ok := svc.diffCache[path]; ok {
-		return nil
+	svc.diffMu.Lock()
+	cached, ok := svc.diffCache[path]
+	svc.diffMu.Unlock()
+	if ok && cached.fresh(path) {
+		return cached.lines, nil
 	}
 
-	svc.Logger.Info("diff not in diffCache, fetching with svn diff command")
+	// stat before diffing so a write that lands mid-diff marks the entry stale
+	modTime, size := statFile(path)
+
+	svc.Logger.Info("diff not in diffCache or stale, fetching with svn diff command")
 	cmd := exec.Command(
-		"svn", "--non-interactive",
+		svc.binary(), "--non-interactive",
 		"diff", path)
 
 	out, err := cmd.Output()
 	if err != nil {
-		return fmt.Errorf("Error running svn diff %s: %w", path, err)
+		return nil, fmt.Errorf("Error running svn diff %s: %w", path, err)
 	}
 	out = bytes.ReplaceAll(out, []byte("\r\n"), []byte("\n")) // normalize line endings
 
 	sep := []byte("====\n")
 	idx := bytes.Index(out, sep)
 	if idx < 0 {
-		return fmt.Errorf("separator for diff not found")
+		return nil, fmt.Errorf("separator for diff not found")
 	}
 
+	lines := []string{""} // empty string so we can still toggle expand
 	changeSep := []byte("\n@@")
 	idx = bytes.Index(out, changeSep)
-	if idx < 0 {
-		empty := []string{""} // empty string so we can still toggle expand
-		svc.diffCache[path] = empty
-		return nil
+	if idx >= 0 {
+		diff := string(out[idx+1:])
+		lines = strings.Split(diff, "\n")
+		if lines[len(lines)-1] == "" {
+			lines = lines[:len(lines)-1]
+		}
 	}
 
-	diff := string(out[idx+1:])
-	lines := strings.Split(diff, "\n")
-	if lines[len(lines)-1] == "" {
-		lines = lines[:len(lines)-1]
+	svc.diffMu.Lock()
+	svc.diffCache[path] = cachedDiff{lines: lines, modTime: modTime, size: size}
+	svc.diffMu.Unlock()
+
+	return lines, nil
+}
+
+// FetchDiffs fetches the diffs for paths using at most maxDiffWorkers svn
+// processes at a time. Paths that fail to diff are logged and left out.
+func (svc *RealService) FetchDiffs(paths []string) map[string][]string {
+	var (
+		wg    sync.WaitGroup
+		mu    sync.Mutex
+		diffs = make(map[string][]string, len(paths))
+	)
+	for _, path := range paths {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			svc.diffWorkers <- struct{}{}
+			defer func() { <-svc.diffWorkers }()
+
+			lines, err := svc.FetchDiff(path)
+			if err != nil {
+				svc.Logger.Warn("Failed to prefetch diff", "path", path, "error", err)
+				return
+			}
+			mu.Lock()
+			diffs[path] = lines
+			mu.Unlock()
+		}()
 	}
-	svc.diffCache[path] = lines
+	wg.Wait()
+	return diffs
+}
 
-	return nil
+// InvalidateDiff drops any cached diff for path. Cache keys are the paths as
+// reported by svn status, so both sides are compared in absolute form.
+func (svc *RealService) InvalidateDiff(path string) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return
+	}
+
+	svc.diffMu.Lock()
+	defer svc.diffMu.Unlock()
+	for key := range svc.diffCache {
+		keyAbs, err := filepath.Abs(key)
+		if err == nil && keyAbs == abs {
+			svc.Logger.Info("Invalidating cached diff", "path", key)
+			delete(svc.diffCache, key)
+		}
+	}
 }
 
-func (svc *RealService) GetDiff(path string) []string {
-	return svc.diffCache[path]
+func (svc *RealService) FetchIgnoreRules() (IgnoreRules, error) {
+	cmd := exec.Command(
+		svc.binary(), "--non-interactive",
+		"propget", "svn:ignore", "-R", svc.WorkingCopyPath, "--xml")
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("error running svn propget svn:ignore: %w", err)
+	}
+
+	var propsXML PropertiesXML
+	if err := xml.Unmarshal(out, &propsXML); err != nil {
+		return nil, fmt.Errorf("error unmarshalling svn propget: %w", err)
+	}
+
+	return parseIgnoreRules(propsXML), nil
+}
+
+// fetchLogBy returns the last limit log entries by author anywhere in the
+// repository, newest first. svn log --limit counts the commits it looks at,
+// not the ones --search matches, so the log is read a page at a time and
+// filtered here until enough are found. Only the newest maxLogPages pages
+// are read, so fewer entries may come back in a busy repository.
+func (svc *RealService) fetchLogBy(ctx context.Context, author string, limit int) ([]LogEntry, error) {
+	var entries []LogEntry
+	start := "HEAD"
+	for range maxLogPages {
+		cmd := exec.CommandContext(ctx,
+			svc.binary(), "--non-interactive",
+			"log", "^/", "-r", start+":0",
+			"--limit", strconv.Itoa(logPageSize), "--xml")
+		cmd.Dir = svc.WorkingCopyPath
+
+		out, err := cmd.Output()
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error running svn log: %w", err)
+		}
+
+		var logXML LogXML
+		if err := xml.Unmarshal(out, &logXML); err != nil {
+			return nil, fmt.Errorf("error unmarshalling svn log: %w", err)
+		}
+
+		for _, entry := range logXML.Entries {
+			if entry.Author != author {
+				continue
+			}
+			entries = append(entries, LogEntry{
+				Revision: entry.Revision,
+				Author:   entry.Author,
+				Message:  strings.TrimSpace(entry.Msg),
+			})
+			if len(entries) == limit {
+				return entries, nil
+			}
+		}
+
+		n := len(logXML.Entries)
+		if n < logPageSize || logXML.Entries[n-1].Revision == 0 {
+			return entries, nil
+		}
+		start = strconv.FormatUint(uint64(logXML.Entries[n-1].Revision-1), 10)
+	}
+	return entries, nil
+}
+
+// FetchLogMessages returns the messages of the last limit commits by author,
+// newest first.
+func (svc *RealService) FetchLogMessages(ctx context.Context, author string, limit int) ([]string, error) {
+	entries, err := svc.fetchLogBy(ctx, author, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	msgs := make([]string, len(entries))
+	for i, entry := range entries {
+		msgs[i] = entry.Message
+	}
+	return msgs, nil
+}
+
+func (svc *RealService) FetchLastCommit(ctx context.Context, author string) (LogEntry, error) {
+	entries, err := svc.fetchLogBy(ctx, author, 1)
+	if err != nil {
+		return LogEntry{}, err
+	}
+	if len(entries) == 0 {
+		return LogEntry{}, fmt.Errorf("no recent commit by %s", author)
+	}
+	return entries[0], nil
+}
+
+// FetchUsername returns the username svn has cached for the server of
+// repoURL, or "" when it has none, as with svn+ssh or svn older than 1.9.
+func (svc *RealService) FetchUsername(repoURL string) (string, error) {
+	cmd := exec.Command(svc.binary(), "--non-interactive", "auth")
+	cmd.Env = englishMessages(os.Environ())
+
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("error running svn auth: %w", stderrError(err))
+	}
+	return parseAuthUsername(string(out), repoURL), nil
+}
+
+// parseAuthUsername finds the username of the first credential in svn auth
+// output whose realm, "<https://host:443> name", is the server of repoURL.
+func parseAuthUsername(out, repoURL string) string {
+	repo, err := url.Parse(repoURL)
+	if err != nil {
+		return ""
+	}
+
+	matches := false
+	for _, line := range strings.Split(out, "\n") {
+		name, value, ok := strings.Cut(strings.TrimSpace(line), ": ")
+		if !ok {
+			continue
+		}
+		switch name {
+		case "Authentication realm":
+			root, _, _ := strings.Cut(strings.TrimPrefix(value, "<"), ">")
+			realm, err := url.Parse(root)
+			matches = err == nil && sameServer(realm, repo)
+		case "Username":
+			if matches {
+				return value
+			}
+		}
+	}
+	return ""
 }
 
-func (svc *RealService) CommitStaged(msg string) error {
-	if svc.RepoStatus.Len(SectionStaged) == 0 {
-		return fmt.Errorf("No files staged to commit")
+var defaultPorts = map[string]string{"http": "80", "https": "443", "svn": "3690"}
+
+func sameServer(a, b *url.URL) bool {
+	port := func(u *url.URL) string {
+		if p := u.Port(); p != "" {
+			return p
+		}
+		return defaultPorts[u.Scheme]
 	}
+	return a.Scheme == b.Scheme &&
+		strings.EqualFold(a.Hostname(), b.Hostname()) &&
+		port(a) == port(b)
+}
 
+// SetLogMessage replaces the svn:log revision property of rev. The server
+// only allows this when its pre-revprop-change hook accepts it. The message
+// is passed in a file, as svn would parse one starting with "-" as options.
+func (svc *RealService) SetLogMessage(rev uint32, msg string) error {
 	if len(msg) == 0 {
 		return fmt.Errorf("Commit message cannot be empty")
 	}
 
+	f, err := os.CreateTemp("", "svnty-log-*.txt")
+	if err != nil {
+		return fmt.Errorf("error writing message file: %w", err)
+	}
+	defer os.Remove(f.Name())
+	_, err = f.WriteString(msg)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("error writing message file: %w", err)
+	}
+
+	cmd := exec.Command(
+		svc.binary(), "--non-interactive",
+		"propset", "--revprop", "-r", strconv.FormatUint(uint64(rev), 10),
+		"svn:log", "-F", f.Name(), "--encoding", "UTF-8", svc.WorkingCopyPath)
+
+	if _, err := cmd.Output(); err != nil {
+		err = stderrError(err)
+		switch {
+		case strings.Contains(err.Error(), "E165006"):
+			return fmt.Errorf("the repository does not allow changing commit messages (no pre-revprop-change hook)")
+		case strings.Contains(err.Error(), "E165001"):
+			return fmt.Errorf("the pre-revprop-change hook rejected the new message of r%d: %w", rev, err)
+		}
+		return fmt.Errorf("error changing message of r%d: %w", rev, err)
+	}
+	return nil
+}
+
+func (svc *RealService) FetchBugtraq() (Bugtraq, error) {
 	cmd := exec.Command(
-		"svn", "--non-interactive",
+		svc.binary(), "--non-interactive",
+		"proplist", "-v", svc.WorkingCopyPath, "--xml")
+
+	out, err := cmd.Output()
+	if err != nil {
+		return Bugtraq{}, fmt.Errorf("error running svn proplist: %w", err)
+	}
+
+	var propsXML PropertiesXML
+	if err := xml.Unmarshal(out, &propsXML); err != nil {
+		return Bugtraq{}, fmt.Errorf("error unmarshalling svn proplist: %w", err)
+	}
+
+	return parseBugtraq(propsXML), nil
+}
+
+var committedRegex = regexp.MustCompile(`Committed revision (\d+)\.`)
+
+// CommitStaged commits the staged changelist and returns the new revision,
+// or 0 when svn succeeded but its output had none, as happens when nothing
+// was left to commit.
+func (svc *RealService) CommitStaged(msg string, opts CommitOptions) (uint32, error) {
+	if len(msg) == 0 {
+		return 0, fmt.Errorf("Commit message cannot be empty")
+	}
+
+	args := []string{
+		"--non-interactive",
 		"commit", svc.WorkingCopyPath,
 		"--changelist", "staged",
-		"-m", msg)
+		"-m", msg,
+	}
+	cmd := exec.Command(svc.binary(), append(args, opts.Args()...)...)
+	cmd.Env = englishMessages(os.Environ())
 
-	_, err := cmd.Output()
+	out, err := cmd.Output()
 	if err != nil {
 		if exitErr, ok := err.(*exec.ExitError); ok {
 			// exitErr.Stderr is a []byte with SVN’s error text
 			stderrText := strings.TrimSpace(string(exitErr.Stderr))
-			return fmt.Errorf("error running commit staged: %s", stderrText)
+			return 0, fmt.Errorf("error running commit staged: %s", stderrText)
 		}
-		return fmt.Errorf("error running commit staged: %w", err)
+		return 0, fmt.Errorf("error running commit staged: %w", err)
 	}
 
+	m := committedRegex.FindSubmatch(out)
+	if m == nil {
+		return 0, nil
+	}
+	rev, _ := strconv.ParseUint(string(m[1]), 10, 32)
+	return uint32(rev), nil
+}
+
+// englishMessages sets LC_MESSAGES=C in env so svn output can be parsed,
+// keeping the character set, which svn needs to read paths and messages.
+// LC_ALL overrides every other variable, so it is split into LC_CTYPE.
+func englishMessages(env []string) []string {
+	out := make([]string, 0, len(env)+2)
+	var all, ctype string
+	for _, kv := range env {
+		name, value, _ := strings.Cut(kv, "=")
+		switch name {
+		case "LC_ALL":
+			all = value
+		case "LC_CTYPE":
+			ctype = value
+		case "LC_MESSAGES":
+		default:
+			out = append(out, kv)
+		}
+	}
+	if all != "" {
+		ctype = all
+	}
+	if ctype != "" {
+		out = append(out, "LC_CTYPE="+ctype)
+	}
+	return append(out, "LC_MESSAGES=C")
+}
+
+// PreflightCheck asks the server whether the staged paths can be committed,
+// looking for conflicts, missing files and out of date paths.
+func (svc *RealService) PreflightCheck() ([]PreflightProblem, error) {
+	cmd := exec.Command(
+		svc.binary(), "--non-interactive",
+		"status", svc.WorkingCopyPath,
+		"--show-updates", "--changelist", "staged", "--xml")
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("error running svn status --show-updates: %w", err)
+	}
+
+	var statusXML StatusXML
+	if err := xml.Unmarshal(out, &statusXML); err != nil {
+		return nil, fmt.Errorf("error unmarshalling svn status: %w", err)
+	}
+
+	return parsePreflight(statusXML), nil
+}
+
+func (svc *RealService) UpdateWorkingCopy(opts UpdateOptions) error {
+	args := []string{"--non-interactive", "update", svc.WorkingCopyPath}
+	cmd := exec.Command(svc.binary(), append(args, opts.Args()...)...)
+
+	if _, err := cmd.Output(); err != nil {
+		return fmt.Errorf("error running svn update: %w", stderrError(err))
+	}
+	return nil
+}
+
+// ResolvePaths marks conflicted paths as resolved, keeping the working file
+// as it is.
+func (svc *RealService) ResolvePaths(paths []string) error {
+	if len(paths) == 0 {
+		return nil
+	}
+
+	args := append([]string{"--non-interactive", "resolve", "--accept", "working"}, paths...)
+	cmd := exec.Command(svc.binary(), args...)
+
+	if _, err := cmd.Output(); err != nil {
+		return fmt.Errorf("error running svn resolve: %w", stderrError(err))
+	}
 	return nil
 }
 
+// stderrError replaces an exit error with svn's error text.
+func stderrError(err error) error {
+	if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+		return errors.New(strings.TrimSpace(string(exitErr.Stderr)))
+	}
+	return err
+}
+
 func StatusToRune(status string) (rune, bool) {
 	switch status {
 	case "added":
@@ -333,6 +737,16 @@ func (rs *RepoStatus) Len(si SectionIdx) int {
 	return len(rs.Sections[si].Paths)
 }
 
+func (rs *RepoStatus) PathStatus(si SectionIdx, idx int) (PathStatus, error) {
+	if si < 0 || si >= NumSections {
+		return PathStatus{}, fmt.Errorf("PathStatus with out of bounds section id called")
+	}
+	if idx < 0 || idx >= len(rs.Sections[si].Paths) {
+		return PathStatus{}, fmt.Errorf("PathStatus with out of bounds idx called")
+	}
+	return rs.Sections[si].Paths[idx], nil
+}
+
 func (rs *RepoStatus) NextNonEmptySection(curr SectionIdx) (next SectionIdx, found bool) {
 	for sec := curr + 1; sec < NumSections; sec++ {
 		if len(rs.Sections[sec].Paths) > 0 {
@@ -401,10 +815,11 @@ type ChangeListXML struct {
 }
 
 type StatusEntryXML struct {
-	XMLName  xml.Name    `xml:"entry"`
-	Path     string      `xml:"path,attr"`
-	WCStatus WCStatusXML `xml:"wc-status"`
-	Commit   CommitXML   `xml:"commit"`
+	XMLName     xml.Name        `xml:"entry"`
+	Path        string          `xml:"path,attr"`
+	WCStatus    WCStatusXML     `xml:"wc-status"`
+	ReposStatus *ReposStatusXML `xml:"repos-status"` // only with --show-updates
+	Commit      CommitXML       `xml:"commit"`
 }
 
 type CommitXML struct {
@@ -414,9 +829,17 @@ type CommitXML struct {
 }
 
 type WCStatusXML struct {
-	XMLName  xml.Name `xml:"wc-status"`
-	Status   string   `xml:"item,attr"`
-	Revision int      `xml:"revision,attr"`
+	XMLName        xml.Name `xml:"wc-status"`
+	Status         string   `xml:"item,attr"`
+	Props          string   `xml:"props,attr"`
+	TreeConflicted bool     `xml:"tree-conflicted,attr"`
+	Revision       int      `xml:"revision,attr"`
+}
+
+type ReposStatusXML struct {
+	XMLName xml.Name `xml:"repos-status"`
+	Status  string   `xml:"item,attr"`
+	Props   string   `xml:"props,attr"`
 }
 
 // SVN INFO XML Structs
@@ -425,6 +848,24 @@ type RepoInfo struct {
 	WorkingPath string
 	RemoteURL   string
 	Revision    uint32
+	UUID        string
+}
+
+// Branch returns the branch or tag name from RemoteURL, following the
+// standard trunk/branches/tags layout, or "" when the URL has none.
+func (ri RepoInfo) Branch() string {
+	parts := strings.Split(strings.TrimSuffix(ri.RemoteURL, "/"), "/")
+	for i, part := range parts {
+		switch part {
+		case "trunk":
+			return part
+		case "branches", "tags":
+			if i+1 < len(parts) {
+				return parts[i+1]
+			}
+		}
+	}
+	return ""
 }
 
 type InfoXML struct {
@@ -433,10 +874,17 @@ type InfoXML struct {
 }
 
 type InfoEntryXML struct {
-	XMLName  xml.Name `xml:"entry"`
-	URL      string   `xml:"url"`
-	Revision uint32   `xml:"revision,attr"`
-	WCInfo   WCInfo   `xml:"wc-info"`
+	XMLName    xml.Name      `xml:"entry"`
+	URL        string        `xml:"url"`
+	Revision   uint32        `xml:"revision,attr"`
+	Repository RepositoryXML `xml:"repository"`
+	WCInfo     WCInfo        `xml:"wc-info"`
+}
+
+type RepositoryXML struct {
+	XMLName xml.Name `xml:"repository"`
+	Root    string   `xml:"root"`
+	UUID    string   `xml:"uuid"`
 }
 
 type WCInfo struct {
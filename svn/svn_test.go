@@ -0,0 +1,38 @@
+package svn
+
+import "testing"
+
+const authOutput = `------------------------------------------------------------------------
+Credential kind: svn.simple
+Authentication realm: <https://other.example.com:443> Other
+Password cache: simple
+Password: [not shown]
+Username: someone
+
+------------------------------------------------------------------------
+Credential kind: svn.simple
+Authentication realm: <https://svn.example.com:443> Example Subversion
+Password cache: gnome-keyring
+Password: [not shown]
+Username: jdoe
+
+Credentials cache in '/home/jdoe/.subversion' contains 2 credentials
+`
+
+func TestParseAuthUsername(t *testing.T) {
+	tests := []struct {
+		repoURL string
+		want    string
+	}{
+		{"https://svn.example.com/repos/project/trunk", "jdoe"},
+		{"https://SVN.example.com:443/repos/project", "jdoe"},
+		{"https://svn.example.com:8443/repos/project", ""},
+		{"http://svn.example.com/repos/project", ""},
+		{"svn+ssh://svn.example.com/repos/project", ""},
+	}
+	for _, tt := range tests {
+		if got := parseAuthUsername(authOutput, tt.repoURL); got != tt.want {
+			t.Errorf("parseAuthUsername(%q) = %q, want %q", tt.repoURL, got, tt.want)
+		}
+	}
+}
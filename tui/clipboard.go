@@ -0,0 +1,19 @@
+package tui
+
+import (
+	"os"
+
+	"github.com/aymanbagabas/go-osc52/v2"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// CopyToClipboard sets the terminal clipboard with an OSC52 sequence. It is
+// written to stderr so it does not interleave with the renderer on stdout.
+func CopyToClipboard(s string) tea.Cmd {
+	return func() tea.Msg {
+		if _, err := osc52.New(s).WriteTo(os.Stderr); err != nil {
+			return RenderErrorMsg(err)
+		}
+		return nil
+	}
+}
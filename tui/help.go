@@ -0,0 +1,107 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/DiwashRai/svnty/styles"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ContextHelp holds the bindings that apply to the current mode and cursor
+// element. Models build it from their keymap, so help never goes stale.
+type ContextHelp struct {
+	Title string
+	Short []key.Binding   // shown in the hint bar
+	Full  [][]key.Binding // columns of the help overlay
+}
+
+func (h ContextHelp) ShortHelp() []key.Binding  { return h.Short }
+func (h ContextHelp) FullHelp() [][]key.Binding { return h.Full }
+
+func NewHelp() help.Model {
+	h := help.New()
+	h.Styles = help.Styles{
+		Ellipsis:       styles.Comment,
+		ShortKey:       styles.StatusRune,
+		ShortDesc:      styles.Comment,
+		ShortSeparator: styles.Comment,
+		FullKey:        styles.StatusRune,
+		FullDesc:       styles.FgDim,
+		FullSeparator:  styles.BaseStyle,
+	}
+	return h
+}
+
+// HintBar renders the short help of ctx on one line. help.Model leaves the
+// space between a key and its description unstyled, so it is given the base
+// background here. It can also overrun h.Width when not even its ellipsis
+// fits, so the line is cut to be sure.
+func HintBar(h help.Model, ctx ContextHelp) string {
+	hints := strings.ReplaceAll(h.ShortHelpView(ctx.ShortHelp()), "\x1b[0m \x1b[", "\x1b[0m"+styles.BaseStyle.Render(" ")+"\x1b[")
+	return Truncate(hints, h.Width, "")
+}
+
+// HelpOverlay renders every column of ctx in a bordered panel of the given
+// width. The columns are joined here rather than by help.Model, whose padding
+// has no background.
+func HelpOverlay(ctx ContextHelp, width int) string {
+	h := NewHelp()
+	var cols []string
+	for _, group := range ctx.FullHelp() {
+		var keys, descs []string
+		for _, b := range group {
+			if !b.Enabled() {
+				continue
+			}
+			keys = append(keys, b.Help().Key)
+			descs = append(descs, b.Help().Desc)
+		}
+		if len(keys) == 0 {
+			continue
+		}
+		if len(cols) > 0 {
+			cols = append(cols, h.Styles.FullSeparator.Render(h.FullSeparator))
+		}
+		cols = append(cols, JoinHorizontalStyled(lipgloss.Top, styles.BaseStyle,
+			JoinVerticalStyled(lipgloss.Left, styles.BaseStyle, renderEach(h.Styles.FullKey, keys)...),
+			styles.BaseStyle.Render(" "),
+			JoinVerticalStyled(lipgloss.Left, styles.BaseStyle, renderEach(h.Styles.FullDesc, descs)...),
+		))
+	}
+
+	body := styles.BorderStyle.
+		BorderTop(false).
+		BorderLeft(true).
+		BorderRight(true).
+		BorderBottom(true).
+		Width(width).
+		PaddingLeft(1).
+		Render(JoinHorizontalStyled(lipgloss.Top, styles.BaseStyle, cols...))
+
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		styles.GetBorderTopWithTitle("Help: "+ctx.Title, width),
+		body,
+	)
+}
+
+// KeyHints renders bindings as "[key] desc" pairs for the footer of a
+// panel, so the footer follows the keymap.
+func KeyHints(bindings ...key.Binding) string {
+	hints := make([]string, 0, len(bindings))
+	for _, b := range bindings {
+		hints = append(hints, "["+b.Help().Key+"] "+b.Help().Desc)
+	}
+	return strings.Join(hints, "  ")
+}
+
+func renderEach(style lipgloss.Style, strs []string) []string {
+	out := make([]string, len(strs))
+	for i, s := range strs {
+		out[i] = style.Render(s)
+	}
+	return out
+}
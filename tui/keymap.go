@@ -0,0 +1,451 @@
+package tui
+
+import (
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/DiwashRai/svnty/config"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textarea"
+)
+
+const KeysFileName = "keys.json"
+
+type AppKeys struct {
+	Quit key.Binding
+}
+
+type StatusKeys struct {
+	Up           key.Binding
+	Down         key.Binding
+	PageUp       key.Binding
+	PageDown     key.Binding
+	ScrollLeft   key.Binding
+	ScrollRight  key.Binding
+	Toggle       key.Binding
+	Diff         key.Binding
+	SideBySide   key.Binding
+	Wrap         key.Binding
+	Stage        key.Binding
+	Unstage      key.Binding
+	Refresh      key.Binding
+	Update       key.Binding
+	Commit       key.Binding
+	Amend        key.Binding
+	CopyRevision key.Binding
+	CopyURL      key.Binding
+	Help         key.Binding
+	Quit         key.Binding
+}
+
+type CommitKeys struct {
+	Submit        key.Binding
+	Cancel        key.Binding
+	Reset         key.Binding
+	Template      key.Binding
+	Editor        key.Binding
+	IssueField    key.Binding
+	Options       key.Binding
+	HistoryUp     key.Binding
+	HistoryDown   key.Binding
+	InsertHistory key.Binding
+	Search        key.Binding
+	ImportLog     key.Binding
+	PreviewUp     key.Binding
+	PreviewDown   key.Binding
+	PreviewToggle key.Binding
+	PreviewPgUp   key.Binding
+	PreviewPgDown key.Binding
+	Help          key.Binding
+}
+
+// SearchKeys apply while typing a history search query.
+type SearchKeys struct {
+	Accept key.Binding
+	Clear  key.Binding
+}
+
+// PreflightKeys apply while the pre-flight problems block the editor.
+type PreflightKeys struct {
+	Update  key.Binding
+	Resolve key.Binding
+	Cancel  key.Binding
+}
+
+// HooksKeys apply while failed hook output blocks the editor. Other keys
+// scroll the output.
+type HooksKeys struct {
+	Force  key.Binding
+	Cancel key.Binding
+}
+
+// UpdateKeys apply while the update menu of the status panel is open.
+type UpdateKeys struct {
+	IgnoreExternals key.Binding
+	Depth           key.Binding
+	Accept          key.Binding
+	Update          key.Binding
+	Close           key.Binding
+}
+
+// OptionsKeys apply while a commit options menu is open. The actions are
+// only offered by the menu of the status panel.
+type OptionsKeys struct {
+	KeepChangelists  key.Binding
+	Depth            key.Binding
+	IncludeExternals key.Binding
+	NoUnlock         key.Binding
+	Commit           key.Binding
+	Amend            key.Binding
+	Close            key.Binding
+}
+
+type KeyMap struct {
+	App       AppKeys
+	Status    StatusKeys
+	Commit    CommitKeys
+	Search    SearchKeys
+	Preflight PreflightKeys
+	Hooks     HooksKeys
+	Options   OptionsKeys
+	Update    UpdateKeys
+}
+
+func binding(help string, keys ...string) key.Binding {
+	return key.NewBinding(key.WithKeys(keys...), key.WithHelp(strings.Join(keys, "/"), help))
+}
+
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		App: AppKeys{
+			Quit: binding("quit", "ctrl+c"),
+		},
+		Status: StatusKeys{
+			Up:           binding("up", "k", "up"),
+			Down:         binding("down", "j", "down"),
+			PageUp:       binding("page up", "pgup", "ctrl+u"),
+			PageDown:     binding("page down", "pgdown", "ctrl+d"),
+			ScrollLeft:   binding("scroll diff left", "h", "shift+left"),
+			ScrollRight:  binding("scroll diff right", "l", "shift+right"),
+			Toggle:       binding("expand/collapse", "enter"),
+			Diff:         binding("toggle diff", "="),
+			SideBySide:   binding("side by side diff", "|"),
+			Wrap:         binding("wrap diff lines", "w"),
+			Stage:        binding("stage", "s"),
+			Unstage:      binding("unstage", "u"),
+			Refresh:      binding("refresh", "g"),
+			Update:       binding("update menu", "U"),
+			Commit:       binding("commit menu", "c"),
+			Amend:        binding("amend last message", "A"),
+			CopyRevision: binding("copy revision", "y"),
+			CopyURL:      binding("copy revision URL", "Y"),
+			Help:         binding("help", "?"),
+			Quit:         binding("quit", "q"),
+		},
+		Commit: CommitKeys{
+			Submit:        binding("commit", "ctrl+d"),
+			Cancel:        binding("back", "esc"),
+			Reset:         binding("clear message", "ctrl+r"),
+			Template:      binding("next template", "ctrl+t"),
+			Editor:        binding("open in $EDITOR", "ctrl+o"),
+			IssueField:    binding("issue field", "ctrl+b"),
+			Options:       binding("commit options", "ctrl+x"),
+			HistoryUp:     binding("history up", "up", "ctrl+k"),
+			HistoryDown:   binding("history down", "down", "ctrl+j"),
+			InsertHistory: binding("insert history", "tab"),
+			Search:        binding("search history", "ctrl+f"),
+			ImportLog:     binding("import svn log", "ctrl+l"),
+			PreviewUp:     binding("previous staged file", "alt+k"),
+			PreviewDown:   binding("next staged file", "alt+j"),
+			PreviewToggle: binding("toggle staged diff", "alt+="),
+			PreviewPgUp:   binding("scroll staged up", "pgup"),
+			PreviewPgDown: binding("scroll staged down", "pgdown"),
+			Help:          binding("help", "f1"), // ? is typed into the message
+		},
+		Search: SearchKeys{
+			Accept: binding("keep filter", "enter"),
+			Clear:  binding("clear filter", "esc"),
+		},
+		Preflight: PreflightKeys{
+			Update:  binding("update", "u"),
+			Resolve: binding("resolve, keep working file", "r"),
+			Cancel:  binding("cancel", "esc", "c"),
+		},
+		Hooks: HooksKeys{
+			Force:  binding("force commit", "f"),
+			Cancel: binding("cancel", "esc", "c"),
+		},
+		Options: OptionsKeys{
+			KeepChangelists:  binding("keep changelists", "k"),
+			Depth:            binding("depth", "d"),
+			IncludeExternals: binding("include externals", "x"),
+			NoUnlock:         binding("no unlock", "u"),
+			Commit:           binding("commit", "c"),
+			Amend:            binding("amend last message", "a"),
+			Close:            binding("close", "esc", "enter", "ctrl+x"),
+		},
+		Update: UpdateKeys{
+			IgnoreExternals: binding("ignore externals", "x"),
+			Depth:           binding("depth", "d"),
+			Accept:          binding("accept", "a"),
+			Update:          binding("update working copy", "u"),
+			Close:           binding("close", "esc", "enter"),
+		},
+	}
+}
+
+// EmacsKeyMap swaps the vim style navigation for emacs keys, and moves the
+// commit editor keys off the emacs editing keys of the message textarea,
+// such as ctrl+d to delete a character and ctrl+f to move forward.
+func EmacsKeyMap() KeyMap {
+	km := DefaultKeyMap()
+	km.Status.Up = binding("up", "ctrl+p", "up")
+	km.Status.Down = binding("down", "ctrl+n", "down")
+	km.Status.PageUp = binding("page up", "alt+v", "pgup")
+	km.Status.PageDown = binding("page down", "ctrl+v", "pgdown")
+	km.Status.ScrollLeft = binding("scroll diff left", "alt+b", "shift+left")
+	km.Status.ScrollRight = binding("scroll diff right", "alt+f", "shift+right")
+	km.Commit.HistoryUp = binding("history up", "up", "alt+p")
+	km.Commit.HistoryDown = binding("history down", "down", "alt+n")
+	km.Commit.Submit = binding("commit", "alt+enter")
+	km.Commit.Template = binding("next template", "alt+t")
+	km.Commit.IssueField = binding("issue field", "alt+i")
+	km.Commit.Search = binding("search history", "ctrl+s")
+	return km
+}
+
+type namedBinding struct {
+	name    string
+	binding *key.Binding
+}
+
+type keyGroup struct {
+	name     string
+	bindings []namedBinding
+}
+
+// groups lists every binding by the names used in the keys file.
+func (km *KeyMap) groups() []keyGroup {
+	return []keyGroup{
+		{"app", []namedBinding{
+			{"quit", &km.App.Quit},
+		}},
+		{"status", []namedBinding{
+			{"up", &km.Status.Up},
+			{"down", &km.Status.Down},
+			{"page_up", &km.Status.PageUp},
+			{"page_down", &km.Status.PageDown},
+			{"scroll_left", &km.Status.ScrollLeft},
+			{"scroll_right", &km.Status.ScrollRight},
+			{"toggle", &km.Status.Toggle},
+			{"diff", &km.Status.Diff},
+			{"side_by_side", &km.Status.SideBySide},
+			{"wrap", &km.Status.Wrap},
+			{"stage", &km.Status.Stage},
+			{"unstage", &km.Status.Unstage},
+			{"refresh", &km.Status.Refresh},
+			{"update", &km.Status.Update},
+			{"commit", &km.Status.Commit},
+			{"amend", &km.Status.Amend},
+			{"copy_revision", &km.Status.CopyRevision},
+			{"copy_url", &km.Status.CopyURL},
+			{"help", &km.Status.Help},
+			{"quit", &km.Status.Quit},
+		}},
+		{"commit", []namedBinding{
+			{"submit", &km.Commit.Submit},
+			{"cancel", &km.Commit.Cancel},
+			{"reset", &km.Commit.Reset},
+			{"template", &km.Commit.Template},
+			{"editor", &km.Commit.Editor},
+			{"issue_field", &km.Commit.IssueField},
+			{"options", &km.Commit.Options},
+			{"history_up", &km.Commit.HistoryUp},
+			{"history_down", &km.Commit.HistoryDown},
+			{"insert_history", &km.Commit.InsertHistory},
+			{"search", &km.Commit.Search},
+			{"import_log", &km.Commit.ImportLog},
+			{"preview_up", &km.Commit.PreviewUp},
+			{"preview_down", &km.Commit.PreviewDown},
+			{"preview_toggle", &km.Commit.PreviewToggle},
+			{"preview_page_up", &km.Commit.PreviewPgUp},
+			{"preview_page_down", &km.Commit.PreviewPgDown},
+			{"help", &km.Commit.Help},
+		}},
+		{"search", []namedBinding{
+			{"accept", &km.Search.Accept},
+			{"clear", &km.Search.Clear},
+		}},
+		{"preflight", []namedBinding{
+			{"update", &km.Preflight.Update},
+			{"resolve", &km.Preflight.Resolve},
+			{"cancel", &km.Preflight.Cancel},
+		}},
+		{"hooks", []namedBinding{
+			{"force", &km.Hooks.Force},
+			{"cancel", &km.Hooks.Cancel},
+		}},
+		{"options", []namedBinding{
+			{"keep_changelists", &km.Options.KeepChangelists},
+			{"depth", &km.Options.Depth},
+			{"include_externals", &km.Options.IncludeExternals},
+			{"no_unlock", &km.Options.NoUnlock},
+			{"commit", &km.Options.Commit},
+			{"amend", &km.Options.Amend},
+			{"close", &km.Options.Close},
+		}},
+		{"update", []namedBinding{
+			{"ignore_externals", &km.Update.IgnoreExternals},
+			{"depth", &km.Update.Depth},
+			{"accept", &km.Update.Accept},
+			{"update", &km.Update.Update},
+			{"close", &km.Update.Close},
+		}},
+	}
+}
+
+// keysFile is the format of KeysFileName: a preset to start from, and the
+// keys of any action to override, grouped by mode.
+type keysFile struct {
+	Preset string                         `json:"preset"`
+	Keys   map[string]map[string][]string `json:"keys"`
+}
+
+// LoadKeyMap reads KeysFileName from the svnty config dir over the chosen
+// preset. The returned warnings cover unknown names and keys bound twice in
+// one mode, and should be shown to the user at startup.
+func LoadKeyMap(logger *slog.Logger) (KeyMap, []string) {
+	km := DefaultKeyMap()
+
+	var file keysFile
+	found, err := config.ReadFile(logger, KeysFileName, &file)
+	if err != nil {
+		return km, []string{fmt.Sprintf("failed to load %s, using default keys: %v", KeysFileName, err)}
+	}
+	if !found {
+		return km, nil
+	}
+
+	var warnings []string
+	switch file.Preset {
+	case "", "vim":
+	case "emacs":
+		km = EmacsKeyMap()
+	default:
+		warnings = append(warnings, fmt.Sprintf("%s: unknown preset %q", KeysFileName, file.Preset))
+	}
+	preset := km // SetKeys replaces the keys, so the copy keeps the preset's
+
+	groups := km.groups()
+	for _, groupName := range slices.Sorted(maps.Keys(file.Keys)) {
+		actions := file.Keys[groupName]
+		group := findGroup(groups, groupName)
+		if group == nil {
+			warnings = append(warnings, fmt.Sprintf("%s: unknown key group %q", KeysFileName, groupName))
+			continue
+		}
+		for _, action := range slices.Sorted(maps.Keys(actions)) {
+			keys := actions[action]
+			b := group.find(action)
+			if b == nil {
+				warnings = append(warnings, fmt.Sprintf("%s: unknown action %s.%s", KeysFileName, groupName, action))
+				continue
+			}
+			b.SetKeys(keys...)
+			b.SetHelp(strings.Join(keys, "/"), b.Help().Desc)
+		}
+	}
+
+	return km, append(warnings, km.Conflicts(preset)...)
+}
+
+func findGroup(groups []keyGroup, name string) *keyGroup {
+	for i := range groups {
+		if groups[i].name == name {
+			return &groups[i]
+		}
+	}
+	return nil
+}
+
+func (g keyGroup) find(name string) *key.Binding {
+	for _, nb := range g.bindings {
+		if nb.name == name {
+			return nb.binding
+		}
+	}
+	return nil
+}
+
+// Conflicts reports keys bound to more than one action within a mode. The
+// app keys are checked before every mode, so they count in all of them. It
+// also reports commit keys that hide an editing key of the message
+// textarea, other than those preset, the keys km started from, takes over
+// on purpose.
+func (km *KeyMap) Conflicts(preset KeyMap) []string {
+	groups := km.groups()
+	app := groups[0]
+
+	var conflicts []string
+	conflicts = append(conflicts, km.editorConflicts(preset)...)
+	for _, group := range groups[1:] {
+		owner := make(map[string]string)
+		for _, g := range []keyGroup{app, group} {
+			for _, nb := range g.bindings {
+				for _, k := range nb.binding.Keys() {
+					name := g.name + "." + nb.name
+					if prev, ok := owner[k]; ok {
+						conflicts = append(conflicts, fmt.Sprintf("key %q is bound to both %s and %s", k, prev, name))
+						continue
+					}
+					owner[k] = name
+				}
+			}
+		}
+	}
+	return conflicts
+}
+
+// editorConflicts reports commit keys that shadow textarea.DefaultKeyMap,
+// which only sees the keys the commit editor lets through.
+func (km *KeyMap) editorConflicts(preset KeyMap) []string {
+	editor := make(map[string]string)
+	for _, b := range textareaBindings() {
+		for _, k := range b.Keys() {
+			editor[k] = b.Help().Desc
+		}
+	}
+
+	taken := make(map[string]bool)
+	for _, nb := range findGroup(preset.groups(), "commit").bindings {
+		for _, k := range nb.binding.Keys() {
+			taken[k] = true
+		}
+	}
+
+	var conflicts []string
+	for _, nb := range findGroup(km.groups(), "commit").bindings {
+		for _, k := range nb.binding.Keys() {
+			if desc, ok := editor[k]; ok && !taken[k] {
+				conflicts = append(conflicts, fmt.Sprintf("key %q of commit.%s hides %q in the message editor", k, nb.name, desc))
+			}
+		}
+	}
+	return conflicts
+}
+
+func textareaBindings() []key.Binding {
+	k := textarea.DefaultKeyMap
+	return []key.Binding{
+		k.CharacterForward, k.CharacterBackward, k.WordForward, k.WordBackward,
+		k.LineNext, k.LinePrevious, k.DeleteWordBackward, k.DeleteWordForward,
+		k.DeleteAfterCursor, k.DeleteBeforeCursor, k.InsertNewline,
+		k.DeleteCharacterBackward, k.DeleteCharacterForward, k.LineStart, k.LineEnd,
+		k.Paste, k.InputBegin, k.InputEnd, k.CapitalizeWordForward,
+		k.LowercaseWordForward, k.UppercaseWordForward, k.TransposeCharacterBackward,
+	}
+}
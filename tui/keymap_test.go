@@ -0,0 +1,56 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPresetsHaveNoConflicts(t *testing.T) {
+	for name, km := range map[string]KeyMap{"vim": DefaultKeyMap(), "emacs": EmacsKeyMap()} {
+		if conflicts := km.Conflicts(km); len(conflicts) > 0 {
+			t.Errorf("%s preset has conflicts: %q", name, conflicts)
+		}
+	}
+}
+
+func TestEmacsKeepsEditingKeys(t *testing.T) {
+	km := EmacsKeyMap()
+	editing := map[string]bool{}
+	for _, b := range textareaBindings() {
+		for _, k := range b.Keys() {
+			editing[k] = true
+		}
+	}
+	for _, nb := range findGroup(km.groups(), "commit").bindings {
+		for _, k := range nb.binding.Keys() {
+			if strings.HasPrefix(k, "ctrl+") && editing[k] {
+				t.Errorf("emacs commit.%s takes the editing key %q", nb.name, k)
+			}
+		}
+	}
+}
+
+func TestEditorConflictReported(t *testing.T) {
+	km := DefaultKeyMap()
+	km.Commit.ImportLog.SetKeys("ctrl+e")
+	conflicts := km.Conflicts(DefaultKeyMap())
+	if len(conflicts) != 1 || !strings.Contains(conflicts[0], "commit.import_log") {
+		t.Errorf("Conflicts() = %q, want the import_log binding hiding line end", conflicts)
+	}
+}
+
+func TestEditorConflictAgainstChosenPreset(t *testing.T) {
+	// vim commits with ctrl+d on purpose, emacs keeps it to delete forward
+	km := EmacsKeyMap()
+	km.Commit.Submit.SetKeys("ctrl+d")
+	conflicts := km.Conflicts(EmacsKeyMap())
+	if len(conflicts) != 1 || !strings.Contains(conflicts[0], "commit.submit") {
+		t.Errorf("Conflicts() = %q, want the submit binding hiding delete forward", conflicts)
+	}
+
+	vim := DefaultKeyMap()
+	vim.Commit.Submit.SetKeys("ctrl+d")
+	if conflicts := vim.Conflicts(DefaultKeyMap()); len(conflicts) > 0 {
+		t.Errorf("vim Conflicts() = %q, want none", conflicts)
+	}
+}
@@ -0,0 +1,177 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/DiwashRai/svnty/styles"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// MenuFlag is a command line option that stays set while its menu is closed,
+// like the arguments of a magit transient. The value lives with whoever owns
+// the options, which Get and Set read and write, so every menu built over the
+// same options shows and edits the same flags.
+type MenuFlag struct {
+	Key     key.Binding
+	Name    string   // the svn option, e.g. --depth
+	Choices []string // values a valued flag cycles through; nil for a switch
+	Get     func() string
+	Set     func(string) // "" unsets the flag
+}
+
+// SwitchFlag is a MenuFlag over a bool.
+func SwitchFlag(k key.Binding, name string, on *bool) MenuFlag {
+	return MenuFlag{
+		Key:  k,
+		Name: name,
+		Get: func() string {
+			if *on {
+				return "on"
+			}
+			return ""
+		},
+		Set: func(v string) { *on = v != "" },
+	}
+}
+
+// ValueFlag is a MenuFlag over a string that cycles through choices.
+func ValueFlag(k key.Binding, name string, choices []string, value *string) MenuFlag {
+	return MenuFlag{
+		Key:     k,
+		Name:    name,
+		Choices: choices,
+		Get:     func() string { return *value },
+		Set:     func(v string) { *value = v },
+	}
+}
+
+func (f MenuFlag) String() string {
+	if f.Choices == nil {
+		return f.Name
+	}
+	if f.Get() == "" {
+		return f.Name + "=default"
+	}
+	return f.Name + "=" + f.Get()
+}
+
+// toggle flips a switch, or moves a valued flag on to its next choice,
+// wrapping around to unset.
+func (f MenuFlag) toggle() {
+	if f.Choices == nil {
+		if f.Get() == "" {
+			f.Set("on")
+		} else {
+			f.Set("")
+		}
+		return
+	}
+	i := 0
+	for j, c := range f.Choices {
+		if c == f.Get() {
+			i = j + 1
+		}
+	}
+	if i < len(f.Choices) {
+		f.Set(f.Choices[i])
+	} else {
+		f.Set("")
+	}
+}
+
+// Action is a command run by its key. In a menu it closes the menu and runs
+// with the flags that are set.
+type Action struct {
+	Key key.Binding
+	Run func() tea.Cmd
+}
+
+// Dispatch runs the first of actions whose key matches msg, and reports
+// whether one did.
+func Dispatch(msg tea.KeyMsg, actions []Action) (tea.Cmd, bool) {
+	for _, a := range actions {
+		if key.Matches(msg, a.Key) {
+			return a.Run(), true
+		}
+	}
+	return nil, false
+}
+
+// Menu is a popup of flags and actions opened by a prefix key. Flag keys
+// toggle flags and keep the menu open, action keys run and close it.
+type Menu struct {
+	Title   string
+	Prefix  key.Binding
+	Flags   []MenuFlag
+	Actions []Action
+	Close   key.Binding
+}
+
+// Update handles a key while the menu is open, and reports whether the menu
+// is still open afterwards.
+func (m *Menu) Update(msg tea.KeyMsg) (tea.Cmd, bool) {
+	if key.Matches(msg, m.Close) {
+		return nil, false
+	}
+	for i := range m.Flags {
+		if key.Matches(msg, m.Flags[i].Key) {
+			m.Flags[i].toggle()
+			return nil, true
+		}
+	}
+	if cmd, ok := Dispatch(msg, m.Actions); ok {
+		return cmd, false
+	}
+	return nil, true
+}
+
+func (m Menu) Help() ContextHelp {
+	var flags, actions []key.Binding
+	for _, f := range m.Flags {
+		flags = append(flags, f.Key)
+	}
+	for _, a := range m.Actions {
+		actions = append(actions, a.Key)
+	}
+	actions = append(actions, m.Close)
+
+	return ContextHelp{
+		Title: strings.ToLower(m.Title),
+		Short: append(append([]key.Binding{}, flags...), actions...),
+		Full:  [][]key.Binding{flags, actions},
+	}
+}
+
+func (m Menu) View(width int) string {
+	var lines []string
+	for _, f := range m.Flags {
+		style := styles.Comment
+		if f.Get() != "" {
+			style = styles.LintWarningStyle
+		}
+		lines = append(lines, styles.StatusRune.Render(f.Key.Help().Key)+
+			styles.BaseStyle.Render("  ")+style.Render(f.String()))
+	}
+	if len(m.Actions) > 0 {
+		lines = append(lines, "")
+	}
+	for _, a := range m.Actions {
+		lines = append(lines, styles.StatusRune.Render(a.Key.Help().Key)+
+			styles.BaseStyle.Render("  "+a.Key.Help().Desc))
+	}
+	lines = append(lines, "", styles.Comment.Render("["+m.Close.Help().Key+"] close"))
+
+	body := styles.BorderStyle.
+		BorderTop(false).
+		BorderLeft(true).
+		BorderRight(true).
+		BorderBottom(true).
+		Width(width).
+		PaddingLeft(1).
+		Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+
+	return lipgloss.JoinVertical(lipgloss.Left, styles.GetBorderTopWithTitle(m.Title, width), body)
+}
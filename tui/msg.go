@@ -1,21 +1,90 @@
 package tui
 
 import (
+	"github.com/DiwashRai/svnty/svn"
+
 	tea "github.com/charmbracelet/bubbletea"
 )
 
 type StatusModeMsg struct{}
+
 type CommitModeMsg struct{}
 
+type AmendModeMsg struct{}
+
 type FetchInfoMsg struct{}
 type FetchStatusMsg struct{}
 
 type RefreshInfoMsg struct{}
 type RefreshStatusPanelMsg struct{}
 
+type InfoFetchedMsg struct {
+	Info svn.RepoInfo
+}
+type StatusFetchedMsg struct {
+	Status svn.RepoStatus
+}
+type BugtraqFetchedMsg struct {
+	Bugtraq svn.Bugtraq
+}
+type UsernameFetchedMsg struct {
+	Username string
+}
+type LogMessagesFetchedMsg struct {
+	Messages []string
+}
+type EditorFinishedMsg struct {
+	Message string
+}
+type PreflightCheckedMsg struct {
+	ID       int // of the commit it was run for
+	Message  string
+	Problems []svn.PreflightProblem
+	Err      error
+}
+type PreflightActionDoneMsg struct {
+	Err error
+}
+
+// DiffFetchedMsg carries a diff the user asked to expand, DiffsFetchedMsg
+// carries diffs fetched in the background that should only be cached.
+type DiffFetchedMsg struct {
+	Path  string
+	Lines []string
+}
+type DiffsFetchedMsg struct {
+	Diffs map[string][]string
+}
+
 type RenderErrorMsg error
-type CommitSuccessMsg struct{}
+type CommitSuccessMsg struct {
+	Message  string
+	Revision uint32
+}
+type UpdatedMsg struct {
+	Err error
+}
+type CommitFailedMsg struct {
+	ID  int
+	Err error
+}
+type LastCommitFetchedMsg struct {
+	Commit svn.LogEntry
+}
+type AmendDoneMsg struct {
+	Revision uint32
+	Message  string
+	Err      error
+}
+type ClearBannerMsg struct {
+	ID int
+}
 type QuitMsg struct{}
+type SaveDraftMsg struct{}
+
+type FilesChangedMsg struct {
+	Paths []string
+}
 
 func StatusMode() tea.Msg {
 	return StatusModeMsg{}
@@ -23,6 +92,9 @@ func StatusMode() tea.Msg {
 func CommitMode() tea.Msg {
 	return CommitModeMsg{}
 }
+func AmendMode() tea.Msg {
+	return AmendModeMsg{}
+}
 
 func FetchInfo() tea.Msg {
 	return FetchInfoMsg{}
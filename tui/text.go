@@ -0,0 +1,22 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Truncate cuts s down to width terminal cells, ending with tail when cut.
+// Widths are measured per grapheme cluster so wide characters and combined
+// runes are never split. A width of 0 or less leaves s unchanged.
+func Truncate(s string, width int, tail string) string {
+	if width <= 0 || ansi.StringWidth(s) <= width {
+		return s
+	}
+	return ansi.Truncate(s, width, tail)
+}
+
+// PadRight pads s with spaces up to width terminal cells.
+func PadRight(s string, width int) string {
+	return s + strings.Repeat(" ", max(0, width-ansi.StringWidth(s)))
+}
@@ -0,0 +1,57 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		name  string
+		s     string
+		width int
+		tail  string
+		want  string
+	}{
+		{"ascii fits", "main.go", 10, "…", "main.go"},
+		{"ascii cut", "main.go", 5, "…", "main…"},
+		{"cjk fits", "日本語.txt", 10, "…", "日本語.txt"},
+		{"cjk cut on cell boundary", "日本語.txt", 6, "…", "日本…"},
+		{"cjk cut before half a wide rune", "日本語.txt", 5, "", "日本"},
+		{"combining marks kept with base", "ééé", 2, "", "éé"},
+		{"zero width leaves s", "日本語", 0, "…", "日本語"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Truncate(tt.s, tt.width, tt.tail)
+			if got != tt.want {
+				t.Errorf("Truncate(%q, %d, %q) = %q, want %q", tt.s, tt.width, tt.tail, got, tt.want)
+			}
+			if tt.width > 0 && ansi.StringWidth(got) > tt.width {
+				t.Errorf("Truncate(%q, %d, %q) is %d cells wide", tt.s, tt.width, tt.tail, ansi.StringWidth(got))
+			}
+		})
+	}
+}
+
+func TestPadRight(t *testing.T) {
+	tests := []struct {
+		name  string
+		s     string
+		width int
+		want  string
+	}{
+		{"ascii", "ab", 4, "ab  "},
+		{"cjk counts two cells per rune", "日本", 6, "日本  "},
+		{"combining marks take no cells", "é", 3, "é  "},
+		{"wider than width", "日本語", 4, "日本語"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PadRight(tt.s, tt.width); got != tt.want {
+				t.Errorf("PadRight(%q, %d) = %q, want %q", tt.s, tt.width, got, tt.want)
+			}
+		})
+	}
+}
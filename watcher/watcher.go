@@ -0,0 +1,155 @@
+package watcher
+
+import (
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/DiwashRai/svnty/tui"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fsnotify/fsnotify"
+)
+
+const (
+	debounceDelay = 300 * time.Millisecond
+	adminDirName  = ".svn"
+)
+
+// Watcher recursively watches a working copy and reports changed files in
+// batches once writes have settled for debounceDelay.
+type Watcher struct {
+	root    string
+	ignore  func(string) bool
+	logger  *slog.Logger
+	fsw     *fsnotify.Watcher
+	changes chan []string
+	done    chan struct{}
+}
+
+func New(root string, ignore func(string) bool, logger *slog.Logger) (*Watcher, error) {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	w := &Watcher{
+		root:    root,
+		ignore:  ignore,
+		logger:  logger,
+		fsw:     fsw,
+		changes: make(chan []string),
+		done:    make(chan struct{}),
+	}
+
+	if err := w.addTree(root); err != nil {
+		fsw.Close()
+		return nil, err
+	}
+
+	go w.run()
+	return w, nil
+}
+
+func (w *Watcher) Close() {
+	close(w.done)
+	w.fsw.Close()
+}
+
+// Wait returns a command that blocks until the next batch of changes. It must
+// be re-issued after every FilesChangedMsg to keep receiving updates.
+func (w *Watcher) Wait() tea.Cmd {
+	return func() tea.Msg {
+		select {
+		case paths := <-w.changes:
+			return tui.FilesChangedMsg{Paths: paths}
+		case <-w.done:
+			return nil
+		}
+	}
+}
+
+func (w *Watcher) skip(path string) bool {
+	if filepath.Base(path) == adminDirName {
+		return true
+	}
+	return w.ignore != nil && w.ignore(path)
+}
+
+func (w *Watcher) addTree(dir string) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			w.logger.Warn("Failed to walk path for watching", "path", path, "error", err)
+			return nil
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if path != dir && w.skip(path) {
+			return filepath.SkipDir
+		}
+		if err := w.fsw.Add(path); err != nil {
+			w.logger.Warn("Failed to watch directory", "path", path, "error", err)
+		}
+		return nil
+	})
+}
+
+func (w *Watcher) run() {
+	var (
+		pending []string
+		timer   = time.NewTimer(debounceDelay)
+		flush   <-chan time.Time
+		out     chan<- []string
+		batch   []string
+	)
+	timer.Stop()
+
+	for {
+		select {
+		case <-w.done:
+			return
+
+		case event, ok := <-w.fsw.Events:
+			if !ok {
+				return
+			}
+			if w.skip(event.Name) || event.Op == fsnotify.Chmod {
+				continue
+			}
+			if event.Has(fsnotify.Create) {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					w.addTree(event.Name)
+				}
+			}
+			if !slices.Contains(pending, event.Name) {
+				pending = append(pending, event.Name)
+			}
+			timer.Reset(debounceDelay)
+			flush = timer.C
+
+		case <-flush:
+			flush = nil
+			for _, p := range pending {
+				if !slices.Contains(batch, p) {
+					batch = append(batch, p)
+				}
+			}
+			pending = nil
+			out = w.changes
+
+		case out <- batch:
+			out = nil
+			batch = nil
+
+		case err, ok := <-w.fsw.Errors:
+			if !ok {
+				return
+			}
+			w.logger.Warn("File watcher error", "error", err)
+		}
+	}
+}